@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ola-rozenfeld/kulami/pkg/render"
+)
+
+// runExport implements the export subcommand, which draws a position of a
+// saved game as an SVG or PNG image, or the game up to it as an animated
+// GIF, by the extension of the output file:
+//
+//	kulami export -load_game game.txt -ply 12 -out position.png
+//	kulami export -load_game game.txt -out game.gif -frame_delay 500ms
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	load := fs.String("load_game", "", "Record file of the game to export.")
+	ply := fs.Int("ply", -1, "Number of moves after which to export the position. -1 exports the final position.")
+	out := fs.String("out", "", "Image file to write, ending with .svg, .png or .gif.")
+	cellSize := fs.Int("cell_size", 40, "Size of a hole of the board, in pixels.")
+	caption := fs.Bool("caption", true, "Whether to show the scores below the board.")
+	legal := fs.Bool("legal_moves", false, "Whether to mark the legal moves.")
+	delay := fs.Duration("frame_delay", time.Second, "How long each move of a GIF is shown.")
+	fs.Parse(args)
+
+	if *load == "" || *out == "" {
+		log.Fatalf("Both -load_game and -out are required.")
+	}
+	rec, err := readRecord(*load)
+	if err != nil {
+		log.Fatalf("Error loading game: %v", err)
+	}
+	b, err := rec.Board()
+	if err != nil {
+		log.Fatalf("Error loading game: %v", err)
+	}
+	if *delay < 0 || *delay > render.MaxFrameDelay {
+		log.Fatalf("Frame delay %v is not between 0 and %v.", *delay, render.MaxFrameDelay)
+	}
+	if *ply < -1 {
+		log.Fatalf("Ply %d is negative; use -1 for the final position.", *ply)
+	}
+	if *ply > b.NumMoves() {
+		log.Fatalf("Ply %d is past the end of the game after %d moves.", *ply, b.NumMoves())
+	}
+	for *ply >= 0 && b.NumMoves() > *ply {
+		b.UndoLastMove()
+	}
+
+	opts := []render.Option{render.CellSize(*cellSize)}
+	if *caption {
+		opts = append(opts, render.Caption())
+	}
+	if *legal {
+		opts = append(opts, render.LegalMoves())
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Error exporting: %v", err)
+	}
+	switch filepath.Ext(*out) {
+	case ".svg":
+		err = render.SVG(f, b, opts...)
+	case ".png":
+		err = render.PNG(f, b, opts...)
+	case ".gif":
+		err = render.GIF(f, b, append(opts, render.FrameDelay(*delay))...)
+	default:
+		f.Close()
+		os.Remove(*out)
+		log.Fatalf("Unknown image format of %s, expected .svg, .png or .gif.", *out)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		log.Fatalf("Error exporting: %v", err)
+	}
+}
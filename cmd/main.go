@@ -2,17 +2,19 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ola-rozenfeld/kulami/pkg/ai"
 	"github.com/ola-rozenfeld/kulami/pkg/board"
+	"github.com/ola-rozenfeld/kulami/pkg/render"
 )
 
 // AIType is the supported levels of AI.
@@ -22,52 +24,118 @@ const (
 	monkey      AIType = "monkey"
 	greedy      AIType = "greedy"
 	calculating AIType = "calculating"
+	mcts        AIType = "mcts"
 )
 
-var aiTypes = []AIType{monkey, greedy, calculating}
+var aiTypes = []AIType{monkey, greedy, calculating, mcts}
 
 var (
-	aiOpp  = flag.Bool("ai_opp", true, "Whether to play vs. an AI opponent or hot-seat.")
-	aiType = flag.String("ai_type", string(monkey), fmt.Sprintf("Type/level of opponent AI. Supported values: %v", aiTypes))
+	aiOpp        = flag.Bool("ai_opp", true, "Whether to play vs. an AI opponent or hot-seat.")
+	aiType       = flag.String("ai_type", string(monkey), fmt.Sprintf("Type/level of opponent AI. Supported values: %v", aiTypes))
+	aiDepth      = flag.Int("ai_depth", 0, "If set, the number of moves the calculating AI looks ahead, instead of its default.")
+	aiTableMB    = flag.Int("ai_table_mb", 16, "Size of the transposition table of the calculating AI, in megabytes. 0 disables it.")
+	aiThreads    = flag.Int("ai_threads", 1, "Number of threads of the mcts AI. Each thread runs the full number of playouts, so more threads play stronger in about the same time, but differently from fewer threads.")
+	moveTime     = flag.Duration("move_time", 0, "If set, the time limit for the AI to think about a move. The calculating and mcts AIs then search until it runs out, unless -ai_depth limits the calculating AI.")
+	randomLayout = flag.Bool("random_layout", false, "Whether to play on a random official layout instead of the sample one.")
+	layoutSeed   = flag.Int64("layout_seed", 0, "Seed of the random layout, for reproducing it. 0 picks a new layout.")
+	saveGame     = flag.String("save_game", "", "If set, the game record is saved to this file when the game ends.")
+	loadGame     = flag.String("load_game", "", "If set, continues the game from this record file instead of starting a new one.")
+	scoring      = flag.String("scoring", board.TileScoring.String(), fmt.Sprintf("Scoring mode: %v counts tile majorities only, %v also counts the largest connected group of marbles.", board.TileScoring, board.GroupScoring))
 )
 
+// sampleLayout is the layout played unless -random_layout is set.
+var sampleLayout = []board.TileLocation{
+	// 6s
+	{Coord: board.Coord{Row: 4, Col: 0}},
+	{Coord: board.Coord{Row: 6, Col: 2}, IsLandscape: true},
+	{Coord: board.Coord{Row: 4, Col: 3}, IsLandscape: true},
+	{Coord: board.Coord{Row: 1, Col: 6}},
+	// 4s
+	{Coord: board.Coord{Row: 0, Col: 4}},
+	{Coord: board.Coord{Row: 2, Col: 4}},
+	{Coord: board.Coord{Row: 2, Col: 2}},
+	{Coord: board.Coord{Row: 4, Col: 6}},
+	{Coord: board.Coord{Row: 7, Col: 5}},
+	// 3s
+	{Coord: board.Coord{Row: 1, Col: 1}, IsLandscape: true},
+	{Coord: board.Coord{Row: 2, Col: 8}},
+	{Coord: board.Coord{Row: 5, Col: 8}, IsLandscape: true},
+	{Coord: board.Coord{Row: 6, Col: 5}, IsLandscape: true},
+	//2s
+	{Coord: board.Coord{Row: 4, Col: 2}},
+	{Coord: board.Coord{Row: 4, Col: 9}, IsLandscape: true},
+	{Coord: board.Coord{Row: 2, Col: 0}},
+	{Coord: board.Coord{Row: 2, Col: 1}},
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "perft":
+			runPerft(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		}
+	}
 	flag.Parse()
 	rand.Seed(time.Now().UTC().UnixNano())
-	sampleBoard := []board.TileLocation{
-		// 6s
-		{Coord: board.Coord{Row: 4, Col: 0}},
-		{Coord: board.Coord{Row: 6, Col: 2}, IsLandscape: true},
-		{Coord: board.Coord{Row: 4, Col: 3}, IsLandscape: true},
-		{Coord: board.Coord{Row: 1, Col: 6}},
-		// 4s
-		{Coord: board.Coord{Row: 0, Col: 4}},
-		{Coord: board.Coord{Row: 2, Col: 4}},
-		{Coord: board.Coord{Row: 2, Col: 2}},
-		{Coord: board.Coord{Row: 4, Col: 6}},
-		{Coord: board.Coord{Row: 7, Col: 5}},
-		// 3s
-		{Coord: board.Coord{Row: 1, Col: 1}, IsLandscape: true},
-		{Coord: board.Coord{Row: 2, Col: 8}},
-		{Coord: board.Coord{Row: 5, Col: 8}, IsLandscape: true},
-		{Coord: board.Coord{Row: 6, Col: 5}, IsLandscape: true},
-		//2s
-		{Coord: board.Coord{Row: 4, Col: 2}},
-		{Coord: board.Coord{Row: 4, Col: 9}, IsLandscape: true},
-		{Coord: board.Coord{Row: 2, Col: 0}},
-		{Coord: board.Coord{Row: 2, Col: 1}},
-	}
-	b, err := board.New(sampleBoard)
+	locs := sampleLayout
+	var opts []board.Option
+	switch *scoring {
+	case board.TileScoring.String():
+	case board.GroupScoring.String():
+		opts = append(opts, board.WithScoring(board.GroupScoring))
+	default:
+		log.Fatalf("Unknown scoring mode %q", *scoring)
+	}
+	if *randomLayout {
+		seed := *layoutSeed
+		if seed == 0 {
+			seed = rand.Int63()
+		}
+		fmt.Printf("Playing on a random layout with seed %d.\n", seed)
+		generated, err := board.GenerateLayout(rand.New(rand.NewSource(seed)), board.DefaultRules(), board.OfficialConstraints())
+		if err != nil {
+			log.Fatalf("Error generating layout: %v", err)
+		}
+		locs = generated
+	}
+	b, err := board.New(locs, opts...)
 	if err != nil {
 		log.Fatalf("Error initializing board: %v", err)
 	}
+	textOpts := []render.Option{render.LegalMoves(), render.LastMoveLines()}
+	if render.IsTerminal(os.Stdout) {
+		textOpts = append(textOpts, render.Color())
+	}
+	rec := &board.Record{Red: "Human", Black: "Human"}
+	if *loadGame != "" {
+		if rec, err = readRecord(*loadGame); err != nil {
+			log.Fatalf("Error loading game: %v", err)
+		}
+		if b, err = rec.Board(); err != nil {
+			log.Fatalf("Error loading game: %v", err)
+		}
+		if b.IsGameOver() {
+			fmt.Print(render.Text(b, textOpts...))
+			fmt.Printf("The game in %s is over: %s.\n", *loadGame, b.Result())
+			return
+		}
+		fmt.Printf("Continuing the game from %s after %d moves.\n", *loadGame, b.NumMoves())
+	}
 	player := 0
+	if !b.IsRedsTurn() {
+		player = 1
+	}
 	playerNames := []string{"Red", "Black"}
 	reader := bufio.NewReader(os.Stdin)
-	round := 1
+	round := 1 + b.NumMoves()/2
 	var aiEngine ai.KulamiAI
 	aiPlayer := 1 //rand.Intn(2)
 	if *aiOpp {
+		rec.Black, rec.BlackAI = "Computer", *aiType
 		fmt.Printf("Playing vs. the %s AI. The AI opponent is playing %s.\n", *aiType, playerNames[aiPlayer])
 		switch *aiType {
 		case string(monkey):
@@ -75,42 +143,45 @@ func main() {
 		case string(greedy):
 			aiEngine = ai.NewGreedyAI(b)
 		case string(calculating):
-			aiEngine = ai.NewCalculatingAI(b)
+			opts := []ai.Option{ai.WithTableSize(*aiTableMB)}
+			if *aiDepth > 0 {
+				opts = append(opts, ai.WithDepth(*aiDepth))
+			} else if *moveTime > 0 {
+				opts = append(opts, ai.WithDepth(0))
+			}
+			aiEngine = ai.NewCalculatingAI(b, opts...)
+		case string(mcts):
+			opts := []ai.Option{ai.WithThreads(*aiThreads)}
+			if *moveTime > 0 {
+				opts = append(opts, ai.WithIterations(math.MaxInt32))
+			}
+			aiEngine = ai.NewMCTSAI(b, opts...)
 		}
 	}
 	for {
-		fmt.Printf("%s", b)
+		fmt.Print(render.Text(b, textOpts...))
 		fmt.Printf("Round %d: it is %s to move. ", round, playerNames[player])
 		var move board.Coord
 		var err error
 		if *aiOpp && player == aiPlayer {
-			if move, err = aiEngine.SuggestMove(); err != nil {
+			if move, err = suggestMove(aiEngine); err != nil {
 				log.Fatalf("An AI error: %v", err)
 			}
-			fmt.Printf("AI chooses %d,%d.\n", move.Row, move.Col)
+			fmt.Printf("AI chooses %v.\n", move)
 		} else {
-			fmt.Printf("Type `resign` to resign, or a move coordinate: ")
+			fmt.Printf("Type `resign` to resign, or a move such as c4: ")
 			text, _ := reader.ReadString('\n')
 			if strings.TrimSpace(text) == "resign" {
-				fmt.Printf("%s resigned. %s wins.\n", playerNames[player], playerNames[1-player])
+				res := b.Forfeit(player == 0, board.Resignation)
+				fmt.Printf("%s resigned. %s.\n", playerNames[player], res)
+				rec.Result = res.Notation()
+				save(rec, b)
 				return
 			}
-			toks := strings.Split(strings.TrimSpace(text), ",")
-			if len(toks) != 2 {
-				fmt.Println("Error: expected coordinate as Row,Col")
-				continue
-			}
-			row, err := strconv.Atoi(toks[0])
-			if err != nil {
-				fmt.Printf("Error %v: expected coordinate as Row,Col\n", err)
-				continue
-			}
-			col, err := strconv.Atoi(toks[1])
-			if err != nil {
-				fmt.Printf("Error %v: expected coordinate as Row,Col\n", err)
+			if move, err = board.ParseCoord(text); err != nil {
+				fmt.Printf("Error: %v\n", err)
 				continue
 			}
-			move = board.Coord{Row: row, Col: col}
 		}
 		if err := b.Move(move, player == 0); err != nil {
 			fmt.Printf("Error: %v\n", err)
@@ -120,17 +191,65 @@ func main() {
 		if player == 0 {
 			round++
 		}
-		if len(b.LegalMoves()) == 0 {
-			redScore := b.RedScore()
-			blackScore := b.BlackScore()
-			if redScore == blackScore {
-				fmt.Printf("The game is a draw with final score %d vs. %d!\n", redScore, blackScore)
-			} else if redScore > blackScore {
-				fmt.Printf("Red wins with final score %d vs. %d!\n", redScore, blackScore)
-			} else {
-				fmt.Printf("Black wins with final score %d vs. %d!\n", blackScore, redScore)
-			}
+		if b.IsGameOver() {
+			res := b.Result()
+			fmt.Print(render.Text(b, textOpts...))
+			fmt.Printf("%s!\n", res)
+			rec.Result = res.Notation()
+			save(rec, b)
 			return
 		}
 	}
 }
+
+// suggestMove asks the AI for a move, within the -move_time if set.
+func suggestMove(a ai.KulamiAI) (board.Coord, error) {
+	if *moveTime <= 0 {
+		return a.SuggestMove()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *moveTime)
+	defer cancel()
+	return a.SuggestMoveContext(ctx)
+}
+
+// readRecord parses and validates a game record file.
+func readRecord(path string) (*board.Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rec, err := board.ParseRecord(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := rec.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return rec, nil
+}
+
+// save writes the game played on the board to the -save_game file, if set.
+// The headers are taken from rec.
+func save(rec *board.Record, b *board.KulamiBoard) {
+	if *saveGame == "" {
+		return
+	}
+	res := board.NewRecord(b)
+	res.Red, res.Black = rec.Red, rec.Black
+	res.RedAI, res.BlackAI = rec.RedAI, rec.BlackAI
+	res.Date = rec.Date
+	if res.Date == "" {
+		res.Date = time.Now().Format("2006.01.02")
+	}
+	res.Result = rec.Result
+	res.Tags = rec.Tags
+	var out strings.Builder
+	if err := board.FormatRecord(&out, res); err != nil {
+		log.Fatalf("Error saving game: %v", err)
+	}
+	if err := os.WriteFile(*saveGame, []byte(out.String()), 0644); err != nil {
+		log.Fatalf("Error saving game: %v", err)
+	}
+	fmt.Printf("Game saved to %s.\n", *saveGame)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+// runPerft implements the perft subcommand, which counts the positions
+// reachable in a number of moves, to verify and time move generation:
+//
+//	kulami perft -depth 4 -divide
+func runPerft(args []string) {
+	fs := flag.NewFlagSet("perft", flag.ExitOnError)
+	depth := fs.Int("depth", 4, "Number of moves to look ahead.")
+	divide := fs.Bool("divide", false, "Whether to break the count down by the first move.")
+	seed := fs.Int64("layout_seed", 0, "If set, counts on the random official layout with this seed instead of the sample one.")
+	load := fs.String("load_game", "", "If set, counts from the position at the end of this record file.")
+	fs.Parse(args)
+	if *depth < 0 {
+		fmt.Fprintf(fs.Output(), "-depth must not be negative, got %d\n", *depth)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	locs := sampleLayout
+	if *seed != 0 {
+		generated, err := board.GenerateLayout(rand.New(rand.NewSource(*seed)), board.DefaultRules(), board.OfficialConstraints())
+		if err != nil {
+			log.Fatalf("Error generating layout: %v", err)
+		}
+		locs = generated
+	}
+	b, err := board.New(locs)
+	if err != nil {
+		log.Fatalf("Error initializing board: %v", err)
+	}
+	if *load != "" {
+		rec, err := readRecord(*load)
+		if err != nil {
+			log.Fatalf("Error loading game: %v", err)
+		}
+		if b, err = rec.Board(); err != nil {
+			log.Fatalf("Error loading game: %v", err)
+		}
+	}
+
+	start := time.Now()
+	var total uint64
+	if *divide {
+		counts := b.PerftDivide(*depth)
+		moves := make([]board.Coord, 0, len(counts))
+		for m := range counts {
+			moves = append(moves, m)
+		}
+		sort.Slice(moves, func(i, j int) bool {
+			return moves[i].Row < moves[j].Row || moves[i].Row == moves[j].Row && moves[i].Col < moves[j].Col
+		})
+		for _, m := range moves {
+			fmt.Printf("%v: %d\n", m, counts[m])
+			total += counts[m]
+		}
+	} else {
+		total = b.Perft(*depth)
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("Perft(%d) = %d in %v (%.0f positions/s)\n", *depth, total, elapsed, float64(total)/elapsed.Seconds())
+}
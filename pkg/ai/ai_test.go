@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var (
+	_ KulamiAI = (*MonkeyAI)(nil)
+	_ KulamiAI = (*GreedyAI)(nil)
+	_ KulamiAI = (*CalculatingAI)(nil)
+	_ KulamiAI = (*MCTSAI)(nil)
+)
+
+func TestSuggestMoveContext(t *testing.T) {
+	b := newBoard(t, 1)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ais := map[string]KulamiAI{
+		"monkey":      NewMonkeyAI(b),
+		"greedy":      NewGreedyAI(b),
+		"calculating": NewCalculatingAI(b, WithDepth(0)),
+		"mcts":        NewMCTSAI(b, WithIterations(1<<30), WithSeed(1)),
+	}
+	for name, ai := range ais {
+		m, err := ai.SuggestMoveContext(cancelled)
+		if err != nil || !contains(b.LegalMoves(), m) {
+			t.Errorf("%s: SuggestMoveContext() with a cancelled context = %v, %v, want a legal move", name, m, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		m, err = ai.SuggestMoveContext(ctx)
+		cancel()
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: SuggestMoveContext() with a timeout of 50ms took %v", name, elapsed)
+		}
+		if err != nil || !contains(b.LegalMoves(), m) {
+			t.Errorf("%s: SuggestMoveContext() with a timeout = %v, %v, want a legal move", name, m, err)
+		}
+	}
+}
+
+func TestSearchContext(t *testing.T) {
+	b := newBoard(t, 1)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := NewCalculatingAI(b).SearchContext(cancelled)
+	if err != nil {
+		t.Fatalf("SearchContext() failed: %v", err)
+	}
+	if res.Depth != 1 || res.Nodes != uint64(len(b.LegalMoves())) {
+		t.Errorf("SearchContext() with a cancelled context = %+v, want depth 1 of %d nodes", res, len(b.LegalMoves()))
+	}
+	if res, err = NewMCTSAI(b, WithSeed(1)).SearchContext(cancelled); err != nil || res.Nodes != 1 {
+		t.Errorf("MCTS SearchContext() with a cancelled context = %+v, %v, want 1 playout", res, err)
+	}
+
+	// A search stopped in the middle returns its last completed iteration.
+	ctx := &countdownContext{Context: context.Background(), n: 20}
+	res, err = NewCalculatingAI(b, WithDepth(0)).SearchContext(ctx)
+	if err != nil {
+		t.Fatalf("SearchContext() failed: %v", err)
+	}
+	if res.Depth < 2 {
+		t.Fatalf("SearchContext() stopped after 20 checks = %+v, want at least depth 2", res)
+	}
+	want, err := NewCalculatingAI(b, WithDepth(res.Depth)).Search()
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if res.Move != want.Move || res.Score != want.Score || res.Nodes <= want.Nodes {
+		t.Errorf("SearchContext() stopped after 20 checks = %+v, want the move and score of %+v, with more nodes", res, want)
+	}
+}
+
+// countdownContext is a context which is done once Err has been called n
+// times, to stop searches at a reproducible point.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
@@ -1,8 +1,8 @@
 package ai
 
 import (
+	"context"
 	"errors"
-	"fmt"
 
 	"github.com/ola-rozenfeld/kulami/pkg/board"
 )
@@ -11,20 +11,241 @@ var ErrNoLegalMoves = errors.New("no legal moves")
 
 // KulamiAI knows how to play the game.
 type KulamiAI interface {
+	// SuggestMove returns the best move the AI can come up with, taking as
+	// long as its options allow.
 	SuggestMove() (board.Coord, error)
+	// SuggestMoveContext is like SuggestMove, but stops thinking when ctx is
+	// done, and returns the best move found until then. The AIs find some
+	// move almost immediately, so they do not fail for a done context.
+	SuggestMoveContext(ctx context.Context) (board.Coord, error)
 }
 
-// CalculatingAI searches for the best move.
+const (
+	kDefaultDepth     = 8
+	kDefaultTableSize = 16 // In megabytes.
+	kInfinity         = 1 << 20
+	// Searches check for a done context every that many nodes.
+	kCheckInterval = 1024
+)
+
+// SearchResult is the outcome of a search for the best move.
+type SearchResult struct {
+	Move  board.Coord
+	Score int        // ScoreDiff for the player to move, expected with best play.
+	Depth int        // Depth in plies of the deepest completed iteration.
+	Nodes uint64     // Number of positions searched.
+	Table TableStats // Use of the transposition table by the search.
+}
+
+// CalculatingAI searches for the best move with alpha-beta pruning, assuming
+// that both players play to maximize their ScoreDiff.
+//
+// The AI keeps the positions it searched in a transposition table, which it
+// reuses in its next searches.
 type CalculatingAI struct {
-	b *board.KulamiBoard
+	b     *board.KulamiBoard
+	opts  options
+	table *TranspositionTable // nil without a table.
 }
 
-// NewCalculatingAI creates a new greedy AI.
-func NewCalculatingAI(b *board.KulamiBoard) *CalculatingAI {
-	return &CalculatingAI{b}
+// NewCalculatingAI creates a new calculating AI.
+func NewCalculatingAI(b *board.KulamiBoard, opts ...Option) *CalculatingAI {
+	a := &CalculatingAI{b: b, opts: newOptions(opts)}
+	if a.opts.tableSize > 0 {
+		a.table = NewTranspositionTable(a.opts.tableSize)
+	}
+	return a
 }
 
 // SuggestMove returns the best move this AI can come up with.
 func (a *CalculatingAI) SuggestMove() (board.Coord, error) {
-	return board.Coord{}, fmt.Errorf("not implemented yet")
+	return a.SuggestMoveContext(context.Background())
+}
+
+// SuggestMoveContext returns the best move found until ctx is done.
+func (a *CalculatingAI) SuggestMoveContext(ctx context.Context) (board.Coord, error) {
+	res, err := a.SearchContext(ctx)
+	return res.Move, err
+}
+
+// Search returns the best move and its score. It searches iteratively deeper
+// up to the configured depth, starting each iteration with the best moves of
+// the last one. Positions at the maximal depth are scored by their
+// ScoreDiff; the search stops early when it reaches the end of the game.
+func (a *CalculatingAI) Search() (SearchResult, error) {
+	return a.SearchContext(context.Background())
+}
+
+// SearchContext is like Search, but stops when ctx is done, with the result
+// of the last completed iteration. The first iteration always completes.
+func (a *CalculatingAI) SearchContext(ctx context.Context) (SearchResult, error) {
+	if a.b.IsGameOver() {
+		return SearchResult{}, ErrNoLegalMoves
+	}
+	s := &search{b: a.b.Clone(), table: a.table}
+	var stats TableStats
+	if a.table != nil {
+		a.table.NewSearch()
+		stats = a.table.Stats()
+	}
+	moves := a.b.LegalMoves()
+	remaining := a.b.RemainingMarbles(true) + a.b.RemainingMarbles(false)
+	var res SearchResult
+	for depth := 1; a.opts.depth == 0 || depth <= a.opts.depth; depth++ {
+		if depth > 1 && ctx.Err() != nil {
+			break
+		}
+		best, score := s.root(moves, depth)
+		if s.aborted {
+			break
+		}
+		res = SearchResult{Move: moves[best], Score: score, Depth: depth, Nodes: s.nodes}
+		// Search the best move first in the next iteration.
+		m := moves[best]
+		copy(moves[1:best+1], moves[:best])
+		moves[0] = m
+		if depth >= remaining {
+			break // The search already sees the end of every game.
+		}
+		s.ctx = ctx
+	}
+	res.Nodes = s.nodes
+	if a.table != nil {
+		res.Table = a.table.Stats().since(stats)
+	}
+	return res, nil
+}
+
+// search is the state of one alpha-beta search.
+type search struct {
+	b     *board.KulamiBoard
+	nodes uint64
+	table *TranspositionTable // nil without a table.
+	// ctx stops the search when done, if set.
+	ctx     context.Context
+	aborted bool
+	// history counts the cutoffs by each move, to try good moves first.
+	history [256]int
+}
+
+// root searches all moves at the root and returns the index of the best one
+// and its score.
+func (s *search) root(moves []board.Coord, depth int) (best, score int) {
+	isRed := s.b.IsRedsTurn()
+	score = -kInfinity
+	for i, m := range moves {
+		mustMove(s.b, m, isRed)
+		v := -s.negamax(depth-1, -kInfinity, -score)
+		s.b.UndoLastMove()
+		if s.aborted {
+			return 0, 0
+		}
+		if v > score {
+			best, score = i, v
+		}
+	}
+	return best, score
+}
+
+// negamax returns the score of the position for the player to move, looking
+// depth plies ahead. Scores outside of alpha and beta are only bounds.
+func (s *search) negamax(depth, alpha, beta int) int {
+	s.nodes++
+	if s.ctx != nil && s.nodes%kCheckInterval == 0 && s.ctx.Err() != nil {
+		s.aborted = true
+	}
+	if s.aborted {
+		return 0
+	}
+	isRed := s.b.IsRedsTurn()
+	if depth == 0 {
+		return s.b.ScoreDiff(isRed)
+	}
+	var hash uint64
+	var hashMove board.Coord
+	hasHashMove := false
+	if s.table != nil {
+		hash = s.b.Hash()
+		if e, ok := s.table.Probe(hash); ok {
+			if e.Depth >= depth {
+				switch {
+				case e.Bound == ExactBound,
+					e.Bound == LowerBound && e.Score >= beta,
+					e.Bound == UpperBound && e.Score <= alpha:
+					return e.Score
+				}
+			}
+			hashMove, hasHashMove = e.Move, true
+		}
+	}
+	moves := s.b.LegalMoves()
+	if len(moves) == 0 {
+		return s.b.ScoreDiff(isRed) // Game over.
+	}
+	s.order(moves)
+	if hasHashMove {
+		// Search the best move of the table first.
+		for i, m := range moves {
+			if m == hashMove {
+				copy(moves[1:i+1], moves[:i])
+				moves[0] = m
+				break
+			}
+		}
+	}
+	alphaOrig := alpha
+	best, bestMove := -kInfinity, moves[0]
+	for _, m := range moves {
+		mustMove(s.b, m, isRed)
+		v := -s.negamax(depth-1, -beta, -alpha)
+		s.b.UndoLastMove()
+		if s.aborted {
+			return 0
+		}
+		if v > best {
+			best, bestMove = v, m
+		}
+		if v > alpha {
+			alpha = v
+		}
+		if alpha >= beta {
+			s.history[historyIndex(m)] += depth * depth
+			break
+		}
+	}
+	if s.table != nil {
+		bound := ExactBound
+		switch {
+		case best <= alphaOrig:
+			bound = UpperBound
+		case best >= beta:
+			bound = LowerBound
+		}
+		s.table.Store(hash, TableEntry{Depth: depth, Bound: bound, Score: best, Move: bestMove})
+	}
+	return best
+}
+
+// mustMove plays a legal move.
+func mustMove(b *board.KulamiBoard, m board.Coord, isRed bool) {
+	if err := b.Move(m, isRed); err != nil {
+		panic(err) // A bug in move generation.
+	}
+}
+
+// order sorts moves by their history of cutoffs, keeping the order of
+// LegalMoves among equals.
+func (s *search) order(moves []board.Coord) {
+	for i := 1; i < len(moves); i++ {
+		m, h := moves[i], s.history[historyIndex(moves[i])]
+		j := i
+		for ; j > 0 && s.history[historyIndex(moves[j-1])] < h; j-- {
+			moves[j] = moves[j-1]
+		}
+		moves[j] = m
+	}
+}
+
+func historyIndex(m board.Coord) int {
+	return m.Row*16 + m.Col
 }
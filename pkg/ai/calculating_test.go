@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+// newBoard returns an empty board on the random official layout of a seed.
+func newBoard(t testing.TB, seed int64) *board.KulamiBoard {
+	t.Helper()
+	locs, err := board.GenerateLayout(rand.New(rand.NewSource(seed)), board.DefaultRules(), board.OfficialConstraints())
+	if err != nil {
+		t.Fatalf("GenerateLayout() failed: %v", err)
+	}
+	b, err := board.New(locs)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	return b
+}
+
+// play plays a game between two AIs on the board, and returns its result.
+func play(t testing.TB, b *board.KulamiBoard, red, black KulamiAI) board.Result {
+	t.Helper()
+	for !b.IsGameOver() {
+		ai := red
+		if !b.IsRedsTurn() {
+			ai = black
+		}
+		m, err := ai.SuggestMove()
+		if err != nil {
+			t.Fatalf("SuggestMove() failed: %v", err)
+		}
+		if err := b.Move(m, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	return b.Result()
+}
+
+func TestCalculatingBeatsGreedy(t *testing.T) {
+	rand.Seed(1)
+	wins, losses := 0, 0
+	for seed := int64(1); seed <= 5; seed++ {
+		for _, calculatingIsRed := range []bool{true, false} {
+			b := newBoard(t, seed)
+			var red, black KulamiAI = NewCalculatingAI(b, WithDepth(6)), NewGreedyAI(b)
+			if !calculatingIsRed {
+				red, black = black, red
+			}
+			res := play(t, b, red, black)
+			if res.Outcome == board.Draw {
+				continue
+			}
+			if (res.Outcome == board.RedWins) == calculatingIsRed {
+				wins++
+			} else {
+				losses++
+			}
+		}
+	}
+	t.Logf("CalculatingAI won %d and lost %d of 10 games against GreedyAI", wins, losses)
+	if wins < 8 {
+		t.Errorf("CalculatingAI won %d and lost %d of 10 games against GreedyAI, want at least 8 wins", wins, losses)
+	}
+}
+
+// minimax returns the score for the player to move with a full search.
+func minimax(b *board.KulamiBoard, depth int) int {
+	isRed := b.IsRedsTurn()
+	moves := b.LegalMoves()
+	if depth == 0 || len(moves) == 0 {
+		return b.ScoreDiff(isRed)
+	}
+	best := -kInfinity
+	for _, m := range moves {
+		b.Move(m, isRed)
+		if v := -minimax(b, depth-1); v > best {
+			best = v
+		}
+		b.UndoLastMove()
+	}
+	return best
+}
+
+func TestCalculatingSearch(t *testing.T) {
+	b := newBoard(t, 1)
+	for i := 0; i < 20; i++ {
+		b.Move(b.LegalMoves()[i%len(b.LegalMoves())], b.IsRedsTurn())
+	}
+	for depth := 1; depth <= 4; depth++ {
+		a := NewCalculatingAI(b, WithDepth(depth))
+		res, err := a.Search()
+		if err != nil {
+			t.Fatalf("Search() failed: %v", err)
+		}
+		if want := minimax(b.Clone(), depth); res.Score != want || res.Depth != depth {
+			t.Errorf("Search() at depth %d = %+v, want score %d", depth, res, want)
+		}
+		c := b.Clone()
+		if err := c.Move(res.Move, c.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%v) failed: %v", res.Move, err)
+		}
+		if got := -minimax(c, depth-1); got != res.Score {
+			t.Errorf("Search() at depth %d chose %v with score %d, want %d", depth, res.Move, got, res.Score)
+		}
+	}
+}
+
+func TestCalculatingEndgame(t *testing.T) {
+	rand.Seed(1)
+	b := newBoard(t, 2)
+	greedy := NewGreedyAI(b)
+	for b.RemainingMarbles(true)+b.RemainingMarbles(false) > 8 {
+		m, err := greedy.SuggestMove()
+		if err != nil {
+			t.Fatalf("SuggestMove() failed: %v", err)
+		}
+		b.Move(m, b.IsRedsTurn())
+	}
+	res, err := NewCalculatingAI(b, WithDepth(20)).Search()
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	want := minimax(b.Clone(), 20)
+	if res.Score != want {
+		t.Errorf("Search() = %+v, want the exact score %d", res, want)
+	}
+	if res.Depth > 8 {
+		t.Errorf("Search() went to depth %d with 8 moves left", res.Depth)
+	}
+
+	// Without a depth limit, the search also ends with the game.
+	for _, depth := range []int{0, -1} {
+		res, err := NewCalculatingAI(b, WithDepth(depth)).Search()
+		if err != nil {
+			t.Fatalf("Search() with depth %d failed: %v", depth, err)
+		}
+		if res.Score != want || res.Depth != 8 {
+			t.Errorf("Search() with depth %d = %+v, want score %d at depth 8", depth, res, want)
+		}
+	}
+
+	for !b.IsGameOver() {
+		b.Move(b.LegalMoves()[0], b.IsRedsTurn())
+	}
+	if _, err := NewCalculatingAI(b).SuggestMove(); err != ErrNoLegalMoves {
+		t.Errorf("SuggestMove() after the game = %v, want %v", err, ErrNoLegalMoves)
+	}
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"math"
 	"math/rand"
 
@@ -19,15 +20,23 @@ func NewGreedyAI(b *board.KulamiBoard) *GreedyAI {
 
 // SuggestMove returns the best move this AI can come up with.
 func (a *GreedyAI) SuggestMove() (board.Coord, error) {
-	moves := a.b.LegalMoves()
-	if len(moves) == 0 {
+	return a.SuggestMoveContext(context.Background())
+}
+
+// SuggestMoveContext returns the best move found until ctx is done.
+func (a *GreedyAI) SuggestMoveContext(ctx context.Context) (board.Coord, error) {
+	if a.b.IsGameOver() {
 		return board.Coord{}, ErrNoLegalMoves
 	}
+	moves := a.b.LegalMoves()
 	b := a.b.Clone()
 	isRed := b.IsRedsTurn()
 	var bestMoves []board.Coord
 	bestValue := math.MinInt32
-	for _, m := range moves {
+	for i, m := range moves {
+		if i > 0 && ctx.Err() != nil {
+			break // Take the best of the moves tried so far.
+		}
 		if err := b.Move(m, isRed); err != nil {
 			return board.Coord{}, err
 		}
@@ -0,0 +1,291 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+const kDefaultIterations = 10000
+
+// MCTSAI searches with Monte Carlo tree search, choosing which moves to
+// explore with UCT: each iteration follows the moves with the best upper
+// confidence bound down the tree, adds one new move to it, and plays the game
+// out to the end to score it. The move explored the most is the best.
+//
+// After a search, the AI keeps the part of the tree after its move, and
+// continues with it in the next search if the board is a continuation of the
+// game.
+//
+// With several threads, the search is root-parallel: every thread grows its
+// own tree from its own random numbers, with all the iterations, and the
+// visits of the moves at the root are added up in the end. The first thread
+// uses the seed of the AI, so a single thread searches exactly as the
+// sequential algorithm.
+type MCTSAI struct {
+	b       *board.KulamiBoard
+	opts    options
+	workers []*mctsWorker
+	// The position the kept trees start from.
+	rootPly  int
+	rootHash uint64
+}
+
+// mctsWorker is the state of one thread of the search.
+type mctsWorker struct {
+	a    *MCTSAI
+	rng  *rand.Rand
+	root *mctsNode // The tree kept from the last search.
+	res  SearchResult
+}
+
+// mctsNode is a position in the search tree.
+type mctsNode struct {
+	move     board.Coord // The move leading to the position.
+	isRed    bool        // Whether the move was made by Red.
+	children []*mctsNode
+	untried  []board.Coord // Legal moves without a child yet.
+	visits   int
+	wins     float64 // Total reward of the player who made the move.
+	diffs    int     // Total final ScoreDiff of the player who made the move.
+}
+
+// NewMCTSAI creates a new Monte Carlo tree search AI.
+func NewMCTSAI(b *board.KulamiBoard, opts ...Option) *MCTSAI {
+	a := &MCTSAI{b: b, opts: newOptions(opts)}
+	for i := 0; i < a.opts.threads || i == 0; i++ {
+		a.workers = append(a.workers, &mctsWorker{a: a, rng: rand.New(rand.NewSource(a.opts.seed + int64(i)))})
+	}
+	return a
+}
+
+// SuggestMove returns the best move this AI can come up with.
+func (a *MCTSAI) SuggestMove() (board.Coord, error) {
+	return a.SuggestMoveContext(context.Background())
+}
+
+// SuggestMoveContext returns the best move found until ctx is done.
+func (a *MCTSAI) SuggestMoveContext(ctx context.Context) (board.Coord, error) {
+	res, err := a.SearchContext(ctx)
+	return res.Move, err
+}
+
+// Search returns the most explored move. Its score is the average final
+// ScoreDiff of the playouts after it, its depth the deepest position of the
+// tree, and its nodes the number of playouts of this search.
+func (a *MCTSAI) Search() (SearchResult, error) {
+	return a.SearchContext(context.Background())
+}
+
+// SearchContext is like Search, but stops when ctx is done. It runs at least
+// one iteration, to have a move.
+func (a *MCTSAI) SearchContext(ctx context.Context) (SearchResult, error) {
+	if a.b.IsGameOver() {
+		return SearchResult{}, ErrNoLegalMoves
+	}
+	if a.opts.timeBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.opts.timeBudget)
+		defer cancel()
+	}
+	var wg sync.WaitGroup
+	for _, w := range a.workers {
+		wg.Add(1)
+		go func(w *mctsWorker) {
+			defer wg.Done()
+			w.search(ctx, a.opts.iterations)
+		}(w)
+	}
+	wg.Wait()
+
+	// Add up the statistics of the moves at the root of all trees, in the
+	// order of the trees, and pick the most visited one.
+	var res SearchResult
+	var moves []*mctsNode
+	byMove := make(map[board.Coord]*mctsNode)
+	for _, w := range a.workers {
+		res.Nodes += w.res.Nodes
+		if w.res.Depth > res.Depth {
+			res.Depth = w.res.Depth
+		}
+		for _, c := range w.root.children {
+			sum := byMove[c.move]
+			if sum == nil {
+				sum = &mctsNode{move: c.move, isRed: c.isRed}
+				byMove[c.move] = sum
+				moves = append(moves, sum)
+			}
+			sum.visits += c.visits
+			sum.wins += c.wins
+			sum.diffs += c.diffs
+		}
+	}
+	best := moves[0]
+	for _, c := range moves[1:] {
+		if c.visits > best.visits {
+			best = c
+		}
+	}
+	res.Move = best.move
+	res.Score = int(math.Round(float64(best.diffs) / float64(best.visits)))
+
+	// Keep the trees after the move for the next search.
+	for _, w := range a.workers {
+		w.root = w.root.child(best.move)
+	}
+	a.rootPly = a.b.NumMoves() + 1
+	c := a.b.Clone()
+	if err := c.Move(best.move, best.isRed); err != nil {
+		return SearchResult{}, err
+	}
+	a.rootHash = c.Hash()
+	return res, nil
+}
+
+// search runs n iterations, or at least one until ctx is done, on the tree
+// of the worker for the position of the board.
+func (w *mctsWorker) search(ctx context.Context, n int) {
+	w.root = w.reuse()
+	w.res = SearchResult{}
+	for i := 0; i == 0 || i < n; i++ {
+		if i > 0 && ctx.Err() != nil {
+			break
+		}
+		if depth := w.iterate(); depth > w.res.Depth {
+			w.res.Depth = depth
+		}
+		w.res.Nodes++
+	}
+}
+
+// reuse returns the node of the kept tree for the position of the board, or
+// a new tree if the board does not continue the game of the last search, or
+// the tree does not have its moves.
+func (w *mctsWorker) reuse() *mctsNode {
+	a, node := w.a, w.root
+	w.root = nil
+	moves := a.b.Moves()
+	if node == nil || len(moves) < a.rootPly {
+		return &mctsNode{untried: a.b.LegalMoves()}
+	}
+	c := a.b.Clone()
+	for c.NumMoves() > a.rootPly {
+		c.UndoLastMove()
+	}
+	if c.Hash() != a.rootHash {
+		return &mctsNode{untried: a.b.LegalMoves()}
+	}
+	for _, m := range moves[a.rootPly:] {
+		if node = node.child(m); node == nil {
+			return &mctsNode{untried: a.b.LegalMoves()}
+		}
+	}
+	return node
+}
+
+// iterate runs one iteration of the search from the root, and returns the
+// depth of the new node.
+func (w *mctsWorker) iterate() int {
+	b := w.a.b.Clone()
+	path := []*mctsNode{w.root}
+	node := w.root
+	// Selection.
+	for len(node.untried) == 0 && len(node.children) > 0 {
+		node = node.selectChild(w.a.opts.exploration)
+		mustMove(b, node.move, node.isRed)
+		path = append(path, node)
+	}
+	// Expansion.
+	if len(node.untried) > 0 {
+		i := w.rng.Intn(len(node.untried))
+		m := node.untried[i]
+		isRed := b.IsRedsTurn()
+		mustMove(b, m, isRed)
+		node = node.expand(m, isRed, b.LegalMoves())
+		path = append(path, node)
+	}
+	// Playout.
+	for {
+		moves := b.LegalMoves()
+		if len(moves) == 0 {
+			break
+		}
+		mustMove(b, w.playoutMove(b, moves), b.IsRedsTurn())
+	}
+	// Backpropagation.
+	diff := b.ScoreDiff(true)
+	for _, n := range path {
+		n.visits++
+		d := diff
+		if !n.isRed {
+			d = -diff
+		}
+		n.diffs += d
+		switch {
+		case d > 0:
+			n.wins++
+		case d == 0:
+			n.wins += 0.5
+		}
+	}
+	return len(path) - 1
+}
+
+// playoutMove picks a move in a playout.
+func (w *mctsWorker) playoutMove(b *board.KulamiBoard, moves []board.Coord) board.Coord {
+	start := w.rng.Intn(len(moves))
+	if w.a.opts.greed <= 0 || w.rng.Float64() >= w.a.opts.greed {
+		return moves[start]
+	}
+	isRed := b.IsRedsTurn()
+	best, bestValue := moves[start], math.MinInt32
+	for i := range moves {
+		m := moves[(start+i)%len(moves)]
+		mustMove(b, m, isRed)
+		if v := b.ScoreDiff(isRed); v > bestValue {
+			best, bestValue = m, v
+		}
+		b.UndoLastMove()
+	}
+	return best
+}
+
+// child returns the child for a move, or nil.
+func (n *mctsNode) child(m board.Coord) *mctsNode {
+	for _, c := range n.children {
+		if c.move == m {
+			return c
+		}
+	}
+	return nil
+}
+
+// expand adds the child for an untried move, with the legal moves after it.
+func (n *mctsNode) expand(m board.Coord, isRed bool, untried []board.Coord) *mctsNode {
+	for i, u := range n.untried {
+		if u == m {
+			n.untried = append(n.untried[:i], n.untried[i+1:]...)
+			break
+		}
+	}
+	c := &mctsNode{move: m, isRed: isRed, untried: untried}
+	n.children = append(n.children, c)
+	return c
+}
+
+// selectChild returns the child with the best upper confidence bound.
+func (n *mctsNode) selectChild(exploration float64) *mctsNode {
+	logN := math.Log(float64(n.visits))
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	for _, c := range n.children {
+		v := c.wins/float64(c.visits) + exploration*math.Sqrt(logN/float64(c.visits))
+		if v > bestValue {
+			best, bestValue = c, v
+		}
+	}
+	return best
+}
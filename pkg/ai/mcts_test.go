@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+func TestMCTSBeatsGreedy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays full games")
+	}
+	rand.Seed(1)
+	wins := 0
+	for seed := int64(1); seed <= 2; seed++ {
+		for _, mctsIsRed := range []bool{true, false} {
+			b := newBoard(t, seed)
+			var red, black KulamiAI = NewMCTSAI(b, WithIterations(1000), WithSeed(seed)), NewGreedyAI(b)
+			if !mctsIsRed {
+				red, black = black, red
+			}
+			if res := play(t, b, red, black); res.Outcome != board.Draw && (res.Outcome == board.RedWins) == mctsIsRed {
+				wins++
+			}
+		}
+	}
+	if wins < 3 {
+		t.Errorf("MCTSAI won %d of 4 games against GreedyAI, want at least 3", wins)
+	}
+}
+
+func TestMCTSReproducible(t *testing.T) {
+	b := newBoard(t, 1)
+	var results []SearchResult
+	for i := 0; i < 2; i++ {
+		res, err := NewMCTSAI(b, WithIterations(300), WithSeed(7), WithGreedyPlayouts(0.5)).Search()
+		if err != nil {
+			t.Fatalf("Search() failed: %v", err)
+		}
+		results = append(results, res)
+	}
+	if results[0] != results[1] {
+		t.Errorf("Search() with the same seed returned %+v and %+v", results[0], results[1])
+	}
+	if results[0].Nodes != 300 {
+		t.Errorf("Search() ran %d playouts, want 300", results[0].Nodes)
+	}
+}
+
+func TestMCTSReuse(t *testing.T) {
+	b := newBoard(t, 1)
+	a := NewMCTSAI(b, WithIterations(2000), WithSeed(1))
+	m, err := a.SuggestMove()
+	if err != nil {
+		t.Fatalf("SuggestMove() failed: %v", err)
+	}
+	if err := b.Move(m, true); err != nil {
+		t.Fatalf("Move(%v) failed: %v", m, err)
+	}
+	kept := a.workers[0].root
+	reply := kept.children[0]
+	for _, c := range kept.children {
+		if c.visits > reply.visits {
+			reply = c
+		}
+	}
+	if err := b.Move(reply.move, false); err != nil {
+		t.Fatalf("Move(%v) failed: %v", reply.move, err)
+	}
+	visits := reply.visits
+	if visits == 0 {
+		t.Fatalf("the kept tree has no visits after %v", reply.move)
+	}
+	if _, err := a.Search(); err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if got, want := reply.visits, visits+2000; got != want {
+		t.Errorf("the tree after %v %v has %d visits, want %d", m, reply.move, got, want)
+	}
+
+	// A different game starts over.
+	other := newBoard(t, 1)
+	other.Move(other.LegalMoves()[1], true)
+	other.Move(other.LegalMoves()[0], false)
+	a.b = other
+	if root := a.workers[0].reuse(); root.visits != 0 {
+		t.Errorf("reuse() for another game returned a tree with %d visits", root.visits)
+	}
+}
+
+func TestMCTSTimeBudget(t *testing.T) {
+	b := newBoard(t, 1)
+	start := time.Now()
+	res, err := NewMCTSAI(b, WithIterations(1<<30), WithTimeBudget(50*time.Millisecond)).Search()
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Search() with a time budget of 50ms took %v", elapsed)
+	}
+	if res.Nodes == 0 || !contains(b.LegalMoves(), res.Move) {
+		t.Errorf("Search() = %+v, want a legal move", res)
+	}
+}
+
+func contains(moves []board.Coord, m board.Coord) bool {
+	for _, c := range moves {
+		if c == m {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMCTSThreads(t *testing.T) {
+	b := newBoard(t, 1)
+	one, err := NewMCTSAI(b, WithIterations(400), WithSeed(3)).Search()
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if res, err := NewMCTSAI(b, WithIterations(400), WithSeed(3), WithThreads(1)).Search(); err != nil || res != one {
+		t.Errorf("Search() with 1 thread = %+v, %v, want %+v as without threads", res, err, one)
+	}
+
+	// The trees of the threads only depend on their seeds, so parallel
+	// searches with a fixed number of iterations are reproducible too.
+	var results []SearchResult
+	for i := 0; i < 2; i++ {
+		a := NewMCTSAI(b, WithIterations(250), WithSeed(3), WithThreads(4))
+		res, err := a.Search()
+		if err != nil {
+			t.Fatalf("Search() failed: %v", err)
+		}
+		if res.Nodes != 1000 || !contains(b.LegalMoves(), res.Move) {
+			t.Errorf("Search() with 4 threads = %+v, want a legal move after 1000 playouts", res)
+		}
+		for i, w := range a.workers {
+			if w.res.Nodes != 250 {
+				t.Errorf("thread %d ran %d playouts, want 250", i, w.res.Nodes)
+			}
+		}
+		results = append(results, res)
+	}
+	if results[0] != results[1] {
+		t.Errorf("Search() with 4 threads and the same seed returned %+v and %+v", results[0], results[1])
+	}
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"math/rand"
 
 	"github.com/ola-rozenfeld/kulami/pkg/board"
@@ -18,9 +19,15 @@ func NewMonkeyAI(b *board.KulamiBoard) *MonkeyAI {
 
 // SuggestMove returns the best move this AI can come up with.
 func (a *MonkeyAI) SuggestMove() (board.Coord, error) {
-	moves := a.b.LegalMoves()
-	if len(moves) == 0 {
+	return a.SuggestMoveContext(context.Background())
+}
+
+// SuggestMoveContext returns a random move right away, so ctx does not
+// matter.
+func (a *MonkeyAI) SuggestMoveContext(ctx context.Context) (board.Coord, error) {
+	if a.b.IsGameOver() {
 		return board.Coord{}, ErrNoLegalMoves
 	}
+	moves := a.b.LegalMoves()
 	return moves[rand.Intn(len(moves))], nil
 }
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"math"
+	"time"
+)
+
+// Option configures an AI.
+type Option func(*options)
+
+type options struct {
+	depth       int
+	iterations  int
+	timeBudget  time.Duration
+	exploration float64
+	greed       float64
+	seed        int64
+	threads     int
+	tableSize   int
+}
+
+// WithDepth sets the maximal depth of the search of CalculatingAI, in plies.
+// The default is 8. With 0, it searches until the end of the game, or until
+// the context of SearchContext is done. Negative depths count as 0.
+func WithDepth(plies int) Option {
+	return func(o *options) {
+		o.depth = plies
+	}
+}
+
+// WithIterations sets the number of playouts of MCTSAI per move and thread.
+// The default is 10000.
+func WithIterations(n int) Option {
+	return func(o *options) {
+		o.iterations = n
+	}
+}
+
+// WithTimeBudget makes MCTSAI stop searching after the given time, even if
+// it has iterations left, like a context with this timeout. Set a large
+// number of iterations to search for exactly this long. Timed searches are
+// not reproducible.
+func WithTimeBudget(d time.Duration) Option {
+	return func(o *options) {
+		o.timeBudget = d
+	}
+}
+
+// WithExploration sets the exploration constant of the UCT formula of
+// MCTSAI. Larger values search more moves, smaller values search the best
+// ones deeper. The default is √2.
+func WithExploration(c float64) Option {
+	return func(o *options) {
+		o.exploration = c
+	}
+}
+
+// WithGreedyPlayouts makes the playouts of MCTSAI take the move with the best
+// immediate score with probability p, instead of a random move. The default
+// is 0, for purely random playouts, which are the fastest.
+func WithGreedyPlayouts(p float64) Option {
+	return func(o *options) {
+		o.greed = p
+	}
+}
+
+// WithSeed seeds the random numbers of MCTSAI, for reproducible searches.
+// By default, the seed is the current time.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithThreads sets the number of threads of MCTSAI. Each thread runs all the
+// iterations, so that more threads search more in about the same time, and
+// never less than one thread would. The default is 1. With a fixed seed and
+// no time limit, searches are reproducible.
+func WithThreads(n int) Option {
+	return func(o *options) {
+		o.threads = n
+	}
+}
+
+// WithTableSize sets the size of the transposition table of CalculatingAI,
+// in megabytes. The default is 16. With 0, it searches without a table.
+func WithTableSize(mb int) Option {
+	return func(o *options) {
+		o.tableSize = mb
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		depth:       kDefaultDepth,
+		iterations:  kDefaultIterations,
+		exploration: math.Sqrt2,
+		seed:        time.Now().UnixNano(),
+		threads:     1,
+		tableSize:   kDefaultTableSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.depth < 0 {
+		o.depth = 0
+	}
+	return o
+}
@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+// Bound tells how the score of a table entry relates to the true score of
+// its position.
+type Bound uint8
+
+const (
+	// ExactBound is the true score.
+	ExactBound Bound = iota + 1
+	// LowerBound means the true score is at least the score, because a move
+	// was found to be too good for the opponent to allow.
+	LowerBound
+	// UpperBound means the true score is at most the score, because no move
+	// reached the score already guaranteed elsewhere.
+	UpperBound
+)
+
+func (b Bound) String() string {
+	switch b {
+	case ExactBound:
+		return "exact"
+	case LowerBound:
+		return "lower"
+	case UpperBound:
+		return "upper"
+	}
+	return "unknown"
+}
+
+// TableEntry is the result of searching a position. The table packs it into
+// a few bytes, so it only stores a Depth between 0 and 255 and a Score
+// between -32768 and 32767; all depths and scores of Kulami fit.
+type TableEntry struct {
+	Depth int // Depth of the search in plies.
+	Bound Bound
+	Score int         // Score for the player to move.
+	Move  board.Coord // Best move found, to search first next time.
+}
+
+// TableStats counts the use of a transposition table.
+type TableStats struct {
+	Probes       uint64 // Lookups of positions.
+	Hits         uint64 // Lookups which found the position.
+	Stores       uint64 // Entries written.
+	Replacements uint64 // Entries written over an entry of another position.
+}
+
+// HitRate returns the fraction of probes which were hits.
+func (s TableStats) HitRate() float64 {
+	if s.Probes == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Probes)
+}
+
+// since returns the use counted after the earlier stats s0.
+func (s TableStats) since(s0 TableStats) TableStats {
+	return TableStats{
+		Probes:       s.Probes - s0.Probes,
+		Hits:         s.Hits - s0.Hits,
+		Stores:       s.Stores - s0.Stores,
+		Replacements: s.Replacements - s0.Replacements,
+	}
+}
+
+// ttEntry is a TableEntry packed into 16 bytes.
+type ttEntry struct {
+	key   uint64 // Hash of the position.
+	score int16
+	depth uint8
+	bound Bound // 0 for an empty entry.
+	move  uint8 // Row*16 + Col of the move.
+	age   uint8 // Search in which the entry was written.
+}
+
+// TranspositionTable caches the results of searching positions by their
+// hash, so that a search does not repeat the work for positions which it
+// reaches by different orders of the same moves.
+//
+// The table has a fixed size. Each position hashes to a bucket of two
+// entries: the first one keeps the deepest search, unless it is left over
+// from an earlier search, and the second one keeps the most recent.
+type TranspositionTable struct {
+	buckets [][2]ttEntry
+	mask    uint64
+	age     uint8
+	stats   TableStats
+}
+
+// NewTranspositionTable returns a table of at most the given size in
+// megabytes, and at least one bucket. Sizes of 0 or less make a table of a
+// single bucket.
+func NewTranspositionTable(mb int) *TranspositionTable {
+	if mb < 0 {
+		mb = 0
+	}
+	n := uint64(mb) << 20 / uint64(unsafe.Sizeof([2]ttEntry{}))
+	size := uint64(1)
+	for size*2 <= n {
+		size *= 2
+	}
+	return &TranspositionTable{buckets: make([][2]ttEntry, size), mask: size - 1}
+}
+
+// Len returns the number of entries the table can hold.
+func (t *TranspositionTable) Len() int {
+	return 2 * len(t.buckets)
+}
+
+// Probe looks up a position by its hash.
+func (t *TranspositionTable) Probe(hash uint64) (TableEntry, bool) {
+	t.stats.Probes++
+	bucket := &t.buckets[hash&t.mask]
+	for i := range bucket {
+		if e := &bucket[i]; e.bound != 0 && e.key == hash {
+			t.stats.Hits++
+			return TableEntry{
+				Depth: int(e.depth),
+				Bound: e.bound,
+				Score: int(e.score),
+				Move:  board.Coord{Row: int(e.move) / 16, Col: int(e.move) % 16},
+			}, true
+		}
+	}
+	return TableEntry{}, false
+}
+
+// Store saves the result of searching a position, replacing an entry of
+// its bucket. Entries with a Depth or Score which does not fit the table are
+// not stored.
+func (t *TranspositionTable) Store(hash uint64, e TableEntry) {
+	if e.Depth < 0 || e.Depth > math.MaxUint8 || e.Score < math.MinInt16 || e.Score > math.MaxInt16 {
+		return
+	}
+	t.stats.Stores++
+	bucket := &t.buckets[hash&t.mask]
+	slot := &bucket[1]
+	switch {
+	case bucket[0].key == hash || bucket[0].bound == 0:
+		slot = &bucket[0]
+	case bucket[1].key == hash || bucket[1].bound == 0:
+	case int(bucket[0].depth) <= e.Depth || bucket[0].age != t.age:
+		slot = &bucket[0]
+	}
+	if slot.key == hash && slot.bound != 0 && int(slot.depth) > e.Depth && slot.age == t.age {
+		return // Keep the deeper result of this search.
+	}
+	if slot.bound != 0 && slot.key != hash {
+		t.stats.Replacements++
+	}
+	*slot = ttEntry{
+		key:   hash,
+		score: int16(e.Score),
+		depth: uint8(e.Depth),
+		bound: e.Bound,
+		move:  uint8(e.Move.Row*16 + e.Move.Col),
+		age:   t.age,
+	}
+}
+
+// NewSearch marks the entries stored so far as older, to be replaced first.
+func (t *TranspositionTable) NewSearch() {
+	t.age++
+}
+
+// Clear removes all entries and resets the statistics.
+func (t *TranspositionTable) Clear() {
+	for i := range t.buckets {
+		t.buckets[i] = [2]ttEntry{}
+	}
+	t.age = 0
+	t.stats = TableStats{}
+}
+
+// Stats returns the use of the table since it was created or cleared.
+func (t *TranspositionTable) Stats() TableStats {
+	return t.stats
+}
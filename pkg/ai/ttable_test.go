@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+func TestTranspositionTableSize(t *testing.T) {
+	tests := []struct {
+		mb   int
+		want int
+	}{
+		{mb: -1, want: 2},
+		{mb: 0, want: 2},
+		{mb: 1, want: 1 << 16},
+		{mb: 3, want: 1 << 17},
+		{mb: 16, want: 1 << 20},
+	}
+	for _, tc := range tests {
+		if got := NewTranspositionTable(tc.mb).Len(); got != tc.want {
+			t.Errorf("NewTranspositionTable(%d).Len() = %d, want %d", tc.mb, got, tc.want)
+		}
+	}
+}
+
+func TestTranspositionTableProbe(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	if _, ok := tt.Probe(42); ok {
+		t.Errorf("Probe(42) found an entry in an empty table")
+	}
+	want := TableEntry{Depth: 5, Bound: LowerBound, Score: -7, Move: board.Coord{Row: 9, Col: 3}}
+	tt.Store(42, want)
+	got, ok := tt.Probe(42)
+	if !ok {
+		t.Fatalf("Probe(42) found no entry after Store")
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Probe(42) returned diff (-want +got):\n%s", diff)
+	}
+	if _, ok := tt.Probe(43); ok {
+		t.Errorf("Probe(43) found an entry of another position")
+	}
+	tt.Store(44, TableEntry{Depth: 256, Bound: ExactBound})
+	tt.Store(45, TableEntry{Depth: 1, Bound: ExactBound, Score: 1 << 15})
+	if _, ok := tt.Probe(44); ok {
+		t.Errorf("Probe(44) found an entry too deep for the table")
+	}
+	if _, ok := tt.Probe(45); ok {
+		t.Errorf("Probe(45) found an entry with a score too large for the table")
+	}
+	if diff := cmp.Diff(TableStats{Probes: 5, Hits: 1, Stores: 1}, tt.Stats()); diff != "" {
+		t.Errorf("Stats() returned diff (-want +got):\n%s", diff)
+	}
+	tt.Clear()
+	if _, ok := tt.Probe(42); ok {
+		t.Errorf("Probe(42) found an entry after Clear")
+	}
+}
+
+func TestTranspositionTableReplacement(t *testing.T) {
+	// A table of a single bucket, so that all positions collide.
+	tt := NewTranspositionTable(0)
+	entry := func(depth int) TableEntry {
+		return TableEntry{Depth: depth, Bound: ExactBound}
+	}
+	found := func(hashes ...uint64) []uint64 {
+		var res []uint64
+		for _, h := range hashes {
+			if _, ok := tt.Probe(h); ok {
+				res = append(res, h)
+			}
+		}
+		return res
+	}
+	tt.Store(1, entry(6))
+	tt.Store(2, entry(2))
+	tt.Store(3, entry(3))
+	if diff := cmp.Diff([]uint64{1, 3}, found(1, 2, 3)); diff != "" {
+		t.Errorf("Positions after a shallow store returned diff (-want +got):\n%s", diff)
+	}
+	tt.Store(4, entry(7))
+	if diff := cmp.Diff([]uint64{3, 4}, found(1, 3, 4)); diff != "" {
+		t.Errorf("Positions after a deeper store returned diff (-want +got):\n%s", diff)
+	}
+	tt.Store(4, entry(1))
+	if e, _ := tt.Probe(4); e.Depth != 7 {
+		t.Errorf("Probe(4) after a shallower store of it = %+v, want depth 7", e)
+	}
+	tt.NewSearch()
+	tt.Store(5, entry(1))
+	if diff := cmp.Diff([]uint64{3, 5}, found(3, 4, 5)); diff != "" {
+		t.Errorf("Positions after a new search returned diff (-want +got):\n%s", diff)
+	}
+	if got := tt.Stats().Replacements; got != 3 {
+		t.Errorf("Stats().Replacements = %d, want 3", got)
+	}
+}
+
+func TestCalculatingTable(t *testing.T) {
+	b := newBoard(t, 3)
+	for i := 0; i < 10; i++ {
+		b.Move(b.LegalMoves()[i%len(b.LegalMoves())], b.IsRedsTurn())
+	}
+	want, err := NewCalculatingAI(b, WithDepth(6), WithTableSize(0)).Search()
+	if err != nil {
+		t.Fatalf("Search() without a table failed: %v", err)
+	}
+	if want.Table != (TableStats{}) {
+		t.Errorf("Search() without a table = %+v, want no table stats", want)
+	}
+	a := NewCalculatingAI(b, WithDepth(6), WithTableSize(1))
+	got, err := a.Search()
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if got.Score != want.Score {
+		t.Errorf("Search() = %+v, want score %d as without a table", got, want.Score)
+	}
+	if got.Nodes >= want.Nodes {
+		t.Errorf("Search() searched %d nodes, want fewer than the %d without a table", got.Nodes, want.Nodes)
+	}
+	if got.Table.Hits == 0 || got.Table.Hits > got.Table.Probes {
+		t.Errorf("Search() = %+v, want some table hits", got)
+	}
+	t.Logf("Search() hit rate %.2f, %d nodes instead of %d", got.Table.HitRate(), got.Nodes, want.Nodes)
+
+	// The next search starts from the entries of the last one.
+	again, err := a.Search()
+	if err != nil {
+		t.Fatalf("Search() again failed: %v", err)
+	}
+	if again.Score != want.Score || again.Nodes >= got.Nodes {
+		t.Errorf("Search() again = %+v, want score %d with fewer than %d nodes", again, want.Score, got.Nodes)
+	}
+	if again.Table.Probes >= got.Table.Probes {
+		t.Errorf("Search() again = %+v, want the stats of this search only", again)
+	}
+}
@@ -0,0 +1,69 @@
+package board
+
+// TileStatus is the state of play on one tile.
+type TileStatus struct {
+	Size    int
+	Red     int    // Red marbles on the tile.
+	Black   int    // Black marbles on the tile.
+	Empty   int    // Empty holes on the tile.
+	Owner   Marble // RedMarble or BlackMarble for the majority, or EmptyHole on a tie.
+	Decided bool   // Whether the owner can no longer change.
+}
+
+// Analysis is a summary of which tiles are still in play, and the range of
+// final scores that remains possible.
+type Analysis struct {
+	Tiles              []TileStatus // Status of each tile, by tile index.
+	RedMin, RedMax     int          // Guaranteed bounds of the final score of Red.
+	BlackMin, BlackMax int          // Guaranteed bounds of the final score of Black.
+}
+
+// Analyze returns the status of every tile, and bounds of the final scores.
+//
+// A tile is decided when it has no empty holes left, or the marble lead on it
+// is larger than its empty holes, so that not even filling them all can
+// change its owner. The minimal score of a player counts the tiles decided in
+// their favor; the maximal score counts all tiles they could still win. Under
+// GroupScoring, the largest group can only grow, up to all marbles of the
+// player if the remaining ones connect all their groups. Once the game is
+// over, the bounds are the final scores.
+func (b *KulamiBoard) Analyze() Analysis {
+	res := Analysis{Tiles: make([]TileStatus, len(b.rules.TileSizes))}
+	for t, size := range b.rules.TileSizes {
+		s := TileStatus{Size: size, Owner: b.TileOwner(t)}
+		s.Red, s.Black = b.TileMarbles(t)
+		s.Empty = size - s.Red - s.Black
+		lead := int(b.tileScore[t])
+		if lead < 0 {
+			lead = -lead
+		}
+		s.Decided = s.Empty == 0 || lead > s.Empty
+		switch {
+		case s.Decided && s.Owner == RedMarble:
+			res.RedMin += size
+			res.RedMax += size
+		case s.Decided && s.Owner == BlackMarble:
+			res.BlackMin += size
+			res.BlackMax += size
+		case !s.Decided:
+			if s.Red+s.Empty > s.Black {
+				res.RedMax += size
+			}
+			if s.Black+s.Empty > s.Red {
+				res.BlackMax += size
+			}
+		}
+		res.Tiles[t] = s
+	}
+	if b.scoring == GroupScoring {
+		res.RedMin += b.redGroup
+		res.RedMax += b.rules.MarblesPerPlayer
+		res.BlackMin += b.blackGroup
+		res.BlackMax += b.rules.MarblesPerPlayer
+	}
+	if b.IsGameOver() {
+		res.RedMin, res.RedMax = b.RedScore(), b.RedScore()
+		res.BlackMin, res.BlackMax = b.BlackScore(), b.BlackScore()
+	}
+	return res
+}
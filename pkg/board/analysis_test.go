@@ -0,0 +1,107 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAnalyze(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	a := b.Analyze()
+	tests := []struct {
+		tile int
+		want TileStatus
+	}{
+		// 4,6: x o / O .
+		{7, TileStatus{Size: 4, Red: 1, Black: 2, Empty: 1, Owner: BlackMarble}},
+		// 1,6: X . / o o / . .
+		{3, TileStatus{Size: 6, Red: 1, Black: 2, Empty: 3, Owner: BlackMarble}},
+		// 6,5: . x .
+		{12, TileStatus{Size: 3, Red: 1, Empty: 2, Owner: RedMarble}},
+		// 2,1: empty.
+		{16, TileStatus{Size: 2, Empty: 2, Owner: EmptyHole}},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.want, a.Tiles[tc.tile]); diff != "" {
+			t.Errorf("Analyze().Tiles[%d] returned diff (-want +got):\n%s", tc.tile, diff)
+		}
+	}
+	if a.RedMin > b.RedScore() || a.RedMax < b.RedScore() || a.BlackMin > b.BlackScore() || a.BlackMax < b.BlackScore() {
+		t.Errorf("Analyze() bounds Red %d-%d, Black %d-%d do not include the current scores %d, %d", a.RedMin, a.RedMax, a.BlackMin, a.BlackMax, b.RedScore(), b.BlackScore())
+	}
+}
+
+func TestAnalyzeDecided(t *testing.T) {
+	locs := []TileLocation{
+		{Coord: Coord{Row: 0, Col: 0}},
+		{Coord: Coord{Row: 0, Col: 1}},
+		{Coord: Coord{Row: 0, Col: 3}},
+	}
+	b, err := NewWithRules(locs, Rules{TileSizes: []int{2, 2, 3}, MarblesPerPlayer: 4, BlockedMoves: 1})
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range []Coord{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 0, Col: 3}, {Row: 1, Col: 1}, {Row: 1, Col: 3}} {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	want := Analysis{
+		Tiles: []TileStatus{
+			{Size: 2, Red: 2, Owner: RedMarble, Decided: true},
+			{Size: 2, Red: 1, Black: 1, Owner: EmptyHole, Decided: true},
+			{Size: 3, Black: 2, Empty: 1, Owner: BlackMarble, Decided: true},
+		},
+		RedMin: 2, RedMax: 2,
+		BlackMin: 3, BlackMax: 3,
+	}
+	if diff := cmp.Diff(want, b.Analyze()); diff != "" {
+		t.Errorf("Analyze() returned diff (-want +got):\n%s", diff)
+	}
+}
+
+// TestAnalyzeBounds checks that decided tiles keep their owners and the final
+// scores stay within the bounds in random games.
+func TestAnalyzeBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for _, scoring := range []Scoring{TileScoring, GroupScoring} {
+		for game := 0; game < 20; game++ {
+			b, err := New(sampleTiles, WithScoring(scoring))
+			if err != nil {
+				t.Fatalf("Error initializing board: %v", err)
+			}
+			var history []Analysis
+			for !b.IsGameOver() {
+				history = append(history, b.Analyze())
+				moves := b.LegalMoves()
+				if err := b.Move(moves[rng.Intn(len(moves))], b.IsRedsTurn()); err != nil {
+					t.Fatalf("Move() failed: %v", err)
+				}
+			}
+			final := b.Analyze()
+			if final.RedMin != b.RedScore() || final.RedMax != b.RedScore() || final.BlackMin != b.BlackScore() || final.BlackMax != b.BlackScore() {
+				t.Errorf("%v game %d: final bounds Red %d-%d, Black %d-%d, want the scores %d, %d", scoring, game, final.RedMin, final.RedMax, final.BlackMin, final.BlackMax, b.RedScore(), b.BlackScore())
+			}
+			for ply, a := range history {
+				if a.RedMin > b.RedScore() || a.RedMax < b.RedScore() || a.BlackMin > b.BlackScore() || a.BlackMax < b.BlackScore() {
+					t.Errorf("%v game %d, ply %d: bounds Red %d-%d, Black %d-%d do not include the final scores %d, %d", scoring, game, ply, a.RedMin, a.RedMax, a.BlackMin, a.BlackMax, b.RedScore(), b.BlackScore())
+				}
+				for tile, s := range a.Tiles {
+					if s.Decided && s.Owner != b.TileOwner(tile) {
+						t.Errorf("%v game %d, ply %d: tile %d was decided for %v, but is owned by %v", scoring, game, ply, tile, s.Owner, b.TileOwner(tile))
+					}
+				}
+			}
+		}
+	}
+}
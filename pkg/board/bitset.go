@@ -0,0 +1,161 @@
+package board
+
+import "math/bits"
+
+const (
+	// Maximal board dimensions supported by the fixed-size representation.
+	kMaxRows  = 16
+	kMaxCols  = 16
+	kMaxCells = kMaxRows * kMaxCols
+)
+
+// bitset is a set of board cells. Cell (row, col) is bit row*kMaxCols+col,
+// so each 64-bit word holds four full rows.
+type bitset [kMaxCells / 64]uint64
+
+var (
+	rowMasks [kMaxRows]bitset // All cells of a row.
+	colMasks [kMaxCols]bitset // All cells of a column.
+)
+
+func init() {
+	for row := 0; row < kMaxRows; row++ {
+		for col := 0; col < kMaxCols; col++ {
+			rowMasks[row].set(cellIndex(row, col))
+			colMasks[col].set(cellIndex(row, col))
+		}
+	}
+}
+
+// cellIndex returns the bit index of a cell.
+func cellIndex(row, col int) int {
+	return row*kMaxCols + col
+}
+
+// cellCoord is the inverse of cellIndex.
+func cellCoord(i int) Coord {
+	return Coord{Row: i / kMaxCols, Col: i % kMaxCols}
+}
+
+func (s *bitset) set(i int) {
+	s[i/64] |= 1 << uint(i%64)
+}
+
+func (s *bitset) clear(i int) {
+	s[i/64] &^= 1 << uint(i%64)
+}
+
+func (s *bitset) has(i int) bool {
+	return s[i/64]&(1<<uint(i%64)) != 0
+}
+
+func (s bitset) and(o bitset) bitset {
+	for i := range s {
+		s[i] &= o[i]
+	}
+	return s
+}
+
+func (s bitset) or(o bitset) bitset {
+	for i := range s {
+		s[i] |= o[i]
+	}
+	return s
+}
+
+func (s bitset) andNot(o bitset) bitset {
+	for i := range s {
+		s[i] &^= o[i]
+	}
+	return s
+}
+
+func (s bitset) isEmpty() bool {
+	for _, w := range s {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s bitset) count() int {
+	n := 0
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// next returns the smallest cell index >= i in the set, or -1 if none.
+func (s bitset) next(i int) int {
+	for w := i / 64; w < len(s); w++ {
+		word := s[w]
+		if w == i/64 {
+			word &= ^uint64(0) << uint(i%64)
+		}
+		if word != 0 {
+			return w*64 + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// appendCoords appends the coordinates of all cells in the set, in row-major
+// order.
+func (s bitset) appendCoords(res []Coord) []Coord {
+	for w, word := range s {
+		for word != 0 {
+			res = append(res, cellCoord(w*64+bits.TrailingZeros64(word)))
+			word &= word - 1
+		}
+	}
+	return res
+}
+
+// shl moves every cell n bits up, 0 < n < 64.
+func (s bitset) shl(n uint) bitset {
+	for i := len(s) - 1; i > 0; i-- {
+		s[i] = s[i]<<n | s[i-1]>>(64-n)
+	}
+	s[0] <<= n
+	return s
+}
+
+// shr moves every cell n bits down, 0 < n < 64.
+func (s bitset) shr(n uint) bitset {
+	for i := 0; i < len(s)-1; i++ {
+		s[i] = s[i]>>n | s[i+1]<<(64-n)
+	}
+	s[len(s)-1] >>= n
+	return s
+}
+
+// neighbors returns all cells orthogonally adjacent to a cell in the set.
+func (s bitset) neighbors() bitset {
+	right := s.shl(1).andNot(colMasks[0])
+	left := s.shr(1).andNot(colMasks[kMaxCols-1])
+	return right.or(left).or(s.shl(kMaxCols)).or(s.shr(kMaxCols))
+}
+
+// largestGroup returns the size of the largest orthogonally connected group
+// of cells in the set.
+func (s bitset) largestGroup() int {
+	best := 0
+	for rest := s; rest.count() > best; {
+		var group bitset
+		group.set(rest.next(0))
+		for {
+			grown := group.or(group.neighbors()).and(rest)
+			if grown == group {
+				break
+			}
+			group = grown
+		}
+		if n := group.count(); n > best {
+			best = n
+		}
+		rest = rest.andNot(group)
+	}
+	return best
+}
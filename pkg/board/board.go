@@ -2,15 +2,14 @@
 package board
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 )
 
 // Kulami has 17 tiles of the following sizes:
 var kTileSizes = []int{6, 6, 6, 6, 4, 4, 4, 4, 4, 3, 3, 3, 3, 2, 2, 2, 2}
 
 const (
-	kNumTiles   = 17
 	kNumMarbles = 28
 	// Possible values of a coordinate on a board.
 	kOutOfBounds = -1
@@ -21,88 +20,134 @@ const (
 
 // Coord is a point in 2D.
 type Coord struct {
-	Col, Row int
+	Col int `json:"col"`
+	Row int `json:"row"`
 }
 
 // KulamiBoard represents a full state in a Kulami game.
 type KulamiBoard struct {
-	end        Coord   // The lower-right corner of the board.
-	tiles      [][]int // Indices of tile by coordinate.
-	marbles    [][]int // Which marble, if any, exists at coordinate.
-	moves      []Coord // All moves made thus far.
-	redScore   int     // Total tiles with red majority so far.
-	blackScore int     // Total tiles with blackMajority so far.
-	tileScore  []int   // Marble advantage for red per tile.
+	*layout
+	red        bitset           // Cells holding a red marble.
+	black      bitset           // Cells holding a black marble.
+	moves      [kMaxCells]uint8 // Cells of all moves made thus far.
+	numMoves   int              // Number of valid entries in moves.
+	redScore   int              // Total tiles with red majority so far.
+	blackScore int              // Total tiles with blackMajority so far.
+	tileScore  [kMaxTiles]int8  // Marble advantage for red per tile.
+	redGroup   int              // Largest connected group of red marbles, if scored.
+	blackGroup int              // Largest connected group of black marbles, if scored.
+	hash       uint64           // Zobrist hash of the position.
+	setup      int              // Number of leading moves placed rather than played.
 }
 
 // RedScore returns the current score of the red player.
 func (b *KulamiBoard) RedScore() int {
-	return b.redScore
+	return b.redScore + b.redGroup
 }
 
 // RedScore returns the current score of the black player.
 func (b *KulamiBoard) BlackScore() int {
+	return b.blackScore + b.blackGroup
+}
+
+// RedTileScore returns the points of the red player for tile majorities.
+func (b *KulamiBoard) RedTileScore() int {
+	return b.redScore
+}
+
+// BlackTileScore returns the points of the black player for tile majorities.
+func (b *KulamiBoard) BlackTileScore() int {
 	return b.blackScore
 }
 
+// RedGroupScore returns the points of the red player for their largest
+// connected group of marbles. It is always 0 unless scoring by GroupScoring.
+func (b *KulamiBoard) RedGroupScore() int {
+	return b.redGroup
+}
+
+// BlackGroupScore returns the points of the black player for their largest
+// connected group of marbles. It is always 0 unless scoring by GroupScoring.
+func (b *KulamiBoard) BlackGroupScore() int {
+	return b.blackGroup
+}
+
+// Scoring returns the scoring mode of the board.
+func (b *KulamiBoard) Scoring() Scoring {
+	return b.scoring
+}
+
 // ScoreDiff returns the current score difference in favor of the given player.
 func (b *KulamiBoard) ScoreDiff(isRed bool) int {
 	if isRed {
-		return b.redScore - b.blackScore
+		return b.RedScore() - b.BlackScore()
 	}
-	return b.blackScore - b.redScore
+	return b.BlackScore() - b.RedScore()
 }
 
 // NumMoves returns the number of moves made by both players.
 func (b *KulamiBoard) NumMoves() int {
-	return len(b.moves)
+	return b.numMoves
 }
 
 // IsRedsTurn returns whether it is Red player's turn. On the first turn,
 // it returns true, however Black moving first is also legal.
 func (b *KulamiBoard) IsRedsTurn() bool {
-	n := b.NumMoves()
-	if n == 0 {
+	if b.numMoves == 0 {
 		return true
 	}
-	m := b.moves[0]
-	redFirst := b.marbles[m.Row][m.Col] == kRedMarble
-	return redFirst == (n%2 == 0)
+	return !b.red.has(int(b.moves[b.numMoves-1]))
 }
 
-// Clone deep copies a board. Useful for an AI to modify while thinking.
+// Clone copies a board. Useful for an AI to modify while thinking.
+// The board state is stored in fixed-size arrays, so this is a single
+// allocation; the immutable tile layout is shared.
 func (b *KulamiBoard) Clone() *KulamiBoard {
-	res := &KulamiBoard{
-		end:        b.end,
-		tiles:      make([][]int, len(b.tiles)),
-		marbles:    make([][]int, len(b.marbles)),
-		moves:      make([]Coord, len(b.moves)),
-		redScore:   b.redScore,
-		blackScore: b.blackScore,
-		tileScore:  make([]int, len(b.tileScore)),
-	}
-	for i := range res.tiles {
-		res.tiles[i] = make([]int, len(b.tiles[i]))
-		copy(res.tiles[i], b.tiles[i])
-	}
-	for i := range res.marbles {
-		res.marbles[i] = make([]int, len(b.marbles[i]))
-		copy(res.marbles[i], b.marbles[i])
-	}
-	copy(res.moves, b.moves)
-	copy(res.tileScore, b.tileScore)
-	return res
+	res := *b
+	return &res
+}
+
+// lastMoves returns the last and the previous moves, or Coord{-1, -1} if
+// there are none.
+func (b *KulamiBoard) lastMoves() (last, prev Coord) {
+	last, prev = Coord{Row: -1, Col: -1}, Coord{Row: -1, Col: -1}
+	if b.numMoves > 0 {
+		last = cellCoord(int(b.moves[b.numMoves-1]))
+	}
+	if b.numMoves > 1 {
+		prev = cellCoord(int(b.moves[b.numMoves-2]))
+	}
+	return last, prev
+}
+
+// tileAt returns the index of the tile at the given cell, or kOutOfBounds.
+func (b *KulamiBoard) tileAt(row, col int) int {
+	return int(b.tiles[cellIndex(row, col)])
+}
+
+// marbleAt returns the content of the given cell.
+func (b *KulamiBoard) marbleAt(row, col int) int {
+	i := cellIndex(row, col)
+	switch {
+	case b.red.has(i):
+		return kRedMarble
+	case b.black.has(i):
+		return kBlackMarble
+	case b.holes.has(i):
+		return kEmptySpace
+	}
+	return kOutOfBounds
 }
 
 // TileLocation represents a complete location of a tile inside a board.
 type TileLocation struct {
-	Coord       Coord
-	IsLandscape bool
+	Coord       Coord `json:"coord"`
+	IsLandscape bool  `json:"landscape,omitempty"`
 }
 
-func (l TileLocation) tileEnd(t int) Coord {
+func (l TileLocation) tileEnd(size int) Coord {
 	end := l.Coord
-	switch kTileSizes[t] {
+	switch size {
 	case 6:
 		if l.IsLandscape {
 			end.Row += 1
@@ -130,192 +175,172 @@ func (l TileLocation) tileEnd(t int) Coord {
 	return end
 }
 
-// New initializes an empty Kulami board from tile coordinates.
-// There should be exactly kNumPieces coordinates corresponding to the
+// New initializes an empty Kulami board from tile coordinates, using the
+// default rules. There should be exactly 17 coordinates corresponding to the
 // upper left corner of each tile.
-func New(locs []TileLocation) (*KulamiBoard, error) {
-	if len(locs) != kNumTiles {
-		return nil, fmt.Errorf("need exactly %v tile locations, got %v", kNumTiles, len(locs))
-	}
-	b := &KulamiBoard{}
-	// Compute the board range.
-	for t, l := range locs {
-		end := l.tileEnd(t)
-		if end.Row > b.end.Row {
-			b.end.Row = end.Row
-		}
-		if end.Col > b.end.Col {
-			b.end.Col = end.Col
-		}
-	}
-	b.tileScore = make([]int, kNumTiles)
-	b.marbles = make([][]int, b.end.Row+1)
-	b.tiles = make([][]int, b.end.Row+1)
-	for i := range b.marbles {
-		b.marbles[i] = make([]int, b.end.Col+1)
-		b.tiles[i] = make([]int, b.end.Col+1)
-		for j := range b.marbles[i] {
-			b.marbles[i][j] = kOutOfBounds
-			b.tiles[i][j] = kOutOfBounds
-		}
-	}
-	for t, l := range locs {
-		end := l.tileEnd(t)
-		for row := l.Coord.Row; row <= end.Row; row++ {
-			for col := l.Coord.Col; col <= end.Col; col++ {
-				b.marbles[row][col] = kEmptySpace
-				if b.tiles[row][col] != kOutOfBounds {
-					return nil, fmt.Errorf("tiles %d and %d intersect on %d,%d", t, b.tiles[row][col], row, col)
-				}
-				b.tiles[row][col] = t
-			}
-		}
-	}
-	return b, nil
+func New(locs []TileLocation, opts ...Option) (*KulamiBoard, error) {
+	return NewWithRules(locs, DefaultRules(), opts...)
+}
+
+// NewWithRules initializes an empty board for a variant of the game. There
+// should be a coordinate for every tile of the rules, corresponding to its
+// upper left corner. An invalid layout results in a *LayoutError.
+func NewWithRules(locs []TileLocation, r Rules, opts ...Option) (*KulamiBoard, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.scoring != TileScoring && o.scoring != GroupScoring {
+		return nil, fmt.Errorf("unknown scoring mode %d", o.scoring)
+	}
+	l, err := newLayout(locs, r, o.strictLayout)
+	if err != nil {
+		return nil, err
+	}
+	l.scoring = o.scoring
+	return &KulamiBoard{layout: l}, nil
 }
 
 // String represenation of the board in the following format:
 //
-// *     0   1   2   3
+// *     a   b   c   d
 //         ---------
-// 0       | o   . |
+// 1       | o   . |
 //         |       -----
-// 1       | .   . | o |
+// 2       | .   . | o |
 //     ------------|   |
-// 2   | .   x   x | o |
+// 3   | .   x   x | o |
 //     ------------|   |
-// 3               | . |
+// 4               | . |
 //                 -----
 func (b *KulamiBoard) String() string {
-	m1, m2 := Coord{Row: -1, Col: -1}, Coord{Row: -1, Col: -1}
-	if len(b.moves) > 0 {
-		m1 = b.moves[len(b.moves)-1]
-	}
-	if len(b.moves) > 1 {
-		m2 = b.moves[len(b.moves)-2]
-	}
-	var res strings.Builder
-	// Print column index.
-	fmt.Fprint(&res, "*  ")
-	for i := range b.marbles[0] {
-		fmt.Fprintf(&res, "%4d", i)
-	}
-	fmt.Fprint(&res, "\n")
-	for row, cols := range b.marbles {
-		// A row of separators between rows.
-		fmt.Fprint(&res, "    ")
-		for col := range cols {
-			if row == 0 && b.tiles[0][col] != kOutOfBounds || row != 0 && b.tiles[row][col] != b.tiles[row-1][col] {
-				fmt.Fprint(&res, "----")
-			} else if col == 0 && b.tiles[row][0] != kOutOfBounds || col != 0 && b.tiles[row][col] != b.tiles[row][col-1] {
-				if b.tiles[row][col] == kOutOfBounds && (row == 0 || b.tiles[row-1][col-1] == kOutOfBounds) {
-					fmt.Fprint(&res, "-   ")
-				} else {
-					fmt.Fprint(&res, "|   ")
-				}
-			} else if col != 0 && row != 0 && b.tiles[row][col] == kOutOfBounds && b.tiles[row-1][col-1] != kOutOfBounds {
-				fmt.Fprint(&res, "-   ")
-			} else {
-				fmt.Fprint(&res, "    ")
-			}
-		}
-		// Close the row from the right with either | or -.
-		if b.tiles[row][b.end.Col] != kOutOfBounds || row != 0 && b.tiles[row-1][b.end.Col] != kOutOfBounds {
-			// | is only for if we're inside the same tile.
-			if row != 0 && b.tiles[row][b.end.Col] == b.tiles[row-1][b.end.Col] {
-				fmt.Fprint(&res, "|")
-			} else {
-				fmt.Fprint(&res, "-")
-			}
-		}
-		fmt.Fprint(&res, "\n")
-		// Print row index.
-		fmt.Fprintf(&res, "%-4d", row)
-		// Print actual content (including marbles).
-		for col, v := range cols {
-			isLast := (row == m1.Row && col == m1.Col || row == m2.Row && col == m2.Col)
-			m := " "
-			switch v {
-			case kEmptySpace:
-				m = "."
-			case kRedMarble:
-				m = "x"
-				if isLast {
-					m = "X"
-				}
-			case kBlackMarble:
-				m = "o"
-				if isLast {
-					m = "O"
-				}
-			}
-			sep := " "
-			if col == 0 && b.tiles[row][0] != kOutOfBounds || col != 0 && b.tiles[row][col] != b.tiles[row][col-1] {
-				sep = "|"
-			}
-			fmt.Fprintf(&res, "%s %s ", sep, m)
-		}
-		// Possibly close the row from the right with |.
-		if b.tiles[row][b.end.Col] != kOutOfBounds {
-			fmt.Fprint(&res, "|")
-		}
-		fmt.Fprint(&res, "\n")
-	}
-	// Last closing row.
-	fmt.Fprint(&res, "    ")
-	for col, t := range b.tiles[b.end.Row] {
-		if t != kOutOfBounds {
-			fmt.Fprint(&res, "----")
-		} else if col != 0 && b.tiles[b.end.Row][col-1] != kOutOfBounds {
-			fmt.Fprint(&res, "-   ")
-		} else {
-			fmt.Fprint(&res, "    ")
-		}
-	}
-	fmt.Fprint(&res, "\n")
-	fmt.Fprintf(&res, "\nScore:\t\tRed: %d\tBlack: %d\n", b.redScore, b.blackScore)
-	return res.String()
+	return b.Diagram(nil)
 }
 
-// Apply a move to the board, if legal.
-func (b *KulamiBoard) Move(c Coord, isRed bool) error {
-	last := Coord{Row: -1, Col: -1}
+// Reasons for a move to be illegal. Errors returned by Move and ValidateMove
+// wrap one of them in a *MoveError.
+var (
+	ErrNotYourTurn       = errors.New("not your turn")
+	ErrGameOver          = errors.New("game is over")
+	ErrOutOfBounds       = errors.New("not a hole on the board")
+	ErrOccupied          = errors.New("hole is occupied")
+	ErrWrongLine         = errors.New("not in the row or column of the last move")
+	ErrBlockedByOpponent = errors.New("tile is blocked by the other player")
+	ErrBlockedBySelf     = errors.New("tile is blocked by the player")
+)
+
+// MoveError describes why a move is illegal.
+type MoveError struct {
+	Err   error // One of the Err* reasons above.
+	Coord Coord // The illegal move.
+	IsRed bool  // The player making the move.
+	msg   string
+}
+
+func (e *MoveError) Error() string {
+	return e.msg
+}
+
+func (e *MoveError) Unwrap() error {
+	return e.Err
+}
+
+func moveError(err error, c Coord, isRed bool, format string, args ...interface{}) *MoveError {
+	return &MoveError{Err: err, Coord: c, IsRed: isRed, msg: fmt.Sprintf(format, args...)}
+}
+
+// ValidateMove returns nil if a move is legal, or a *MoveError explaining
+// why it is not. The board is not changed.
+func (b *KulamiBoard) ValidateMove(c Coord, isRed bool) error {
+	last, _ := b.lastMoves()
 	numMoves := b.NumMoves()
-	if numMoves != 0 {
-		last = b.moves[numMoves-1]
+	if numMoves > 0 && isRed == b.red.has(cellIndex(last.Row, last.Col)) {
+		return moveError(ErrNotYourTurn, c, isRed, "it is now the other player's turn")
 	}
-	if numMoves > 0 && isRed == (b.marbles[last.Row][last.Col] == kRedMarble) {
-		return fmt.Errorf("it is now the other player's turn")
+	if numMoves == b.maxMoves {
+		return moveError(ErrGameOver, c, isRed, "game is over, out of marbles")
 	}
-	if len(b.moves) == kNumMarbles*2 {
-		return fmt.Errorf("game is over, out of marbles")
+	err := b.validateHole(c, isRed, last)
+	if err != nil && b.legalMask().isEmpty() {
+		return moveError(ErrGameOver, c, isRed, "game is over, no legal moves")
 	}
-	if c.Row < 0 || c.Row > b.end.Row || c.Col < 0 || c.Col > b.end.Col {
-		return fmt.Errorf("%d,%d is not a legal move", c.Row, c.Col)
+	return err
+}
+
+// validateHole checks the hole of a move, with the last move at last.
+func (b *KulamiBoard) validateHole(c Coord, isRed bool, last Coord) error {
+	numMoves := b.NumMoves()
+	if !b.inBounds(c) || b.marbleAt(c.Row, c.Col) == kOutOfBounds {
+		return moveError(ErrOutOfBounds, c, isRed, "%v is not a legal move, it is not a hole on the board", c)
 	}
-	if b.marbles[c.Row][c.Col] != kEmptySpace || last.Row >= 0 && last.Row != c.Row && last.Col != c.Col {
-		return fmt.Errorf("%d,%d is not a legal move", c.Row, c.Col)
+	if b.marbleAt(c.Row, c.Col) != kEmptySpace {
+		return moveError(ErrOccupied, c, isRed, "%v is not a legal move, the hole is occupied", c)
 	}
-	if last.Row >= 0 && b.tiles[c.Row][c.Col] == b.tiles[last.Row][last.Col] {
-		return fmt.Errorf("%d,%d is not a legal move, the tile is blocked by the other player", c.Row, c.Col)
+	if b.rules.RowColumn && last.Row >= 0 && last.Row != c.Row && last.Col != c.Col {
+		return moveError(ErrWrongLine, c, isRed, "%v is not a legal move, it is not in the row or column of the last move", c)
 	}
-	if len(b.moves) > 1 {
-		pre := b.moves[numMoves-2]
-		if b.tiles[c.Row][c.Col] == b.tiles[pre.Row][pre.Col] {
-			return fmt.Errorf("%d,%d is not a legal move, the tile is blocked by you", c.Row, c.Col)
+	tile := b.tileAt(c.Row, c.Col)
+	for k := 1; k <= b.rules.BlockedMoves && k <= numMoves; k++ {
+		if tile != int(b.tiles[b.moves[numMoves-k]]) {
+			continue
+		}
+		if k%2 == 1 {
+			return moveError(ErrBlockedByOpponent, c, isRed, "%v is not a legal move, the tile is blocked by the other player", c)
 		}
+		return moveError(ErrBlockedBySelf, c, isRed, "%v is not a legal move, the tile is blocked by you", c)
 	}
-	b.moves = append(b.moves, c)
+	return nil
+}
+
+// Apply a move to the board, if legal. Otherwise, return a *MoveError.
+func (b *KulamiBoard) Move(c Coord, isRed bool) error {
+	if err := b.ValidateMove(c, isRed); err != nil {
+		return err
+	}
+	b.place(cellIndex(c.Row, c.Col), isRed)
+	return nil
+}
+
+// SetupMoves returns the number of leading moves which were placed on the
+// board rather than played, e.g. by ParseDiagram. They were not checked for
+// legality and need not be in the order they were played.
+func (b *KulamiBoard) SetupMoves() int {
+	return b.setup
+}
+
+// placeSetup places a set-up move. The only check is that it is on an empty
+// hole, and that no move has been played yet.
+func (b *KulamiBoard) placeSetup(c Coord, isRed bool) error {
+	if b.numMoves != b.setup {
+		return fmt.Errorf("cannot set up %v after moves were played", c)
+	}
+	if b.numMoves == b.maxMoves {
+		return fmt.Errorf("cannot set up %v, out of marbles", c)
+	}
+	if !b.inBounds(c) || b.marbleAt(c.Row, c.Col) != kEmptySpace {
+		return fmt.Errorf("cannot set up %v, not an empty hole", c)
+	}
+	b.place(cellIndex(c.Row, c.Col), isRed)
+	b.setup++
+	return nil
+}
+
+// place puts a marble on cell i and updates the scores.
+func (b *KulamiBoard) place(i int, isRed bool) {
+	tile := int(b.tiles[i])
+	b.hash ^= b.turnHash() ^ marbleHash(i, isRed)
+	b.moves[b.numMoves] = uint8(i)
+	b.numMoves++
 	if isRed {
-		b.marbles[c.Row][c.Col] = kRedMarble
+		b.red.set(i)
 	} else {
-		b.marbles[c.Row][c.Col] = kBlackMarble
+		b.black.set(i)
 	}
-	tile := b.tiles[c.Row][c.Col]
+	b.hash ^= b.turnHash()
 	curScore := b.tileScore[tile]
 	delta := 0
 	if curScore == 0 || curScore == -1 && isRed || curScore == 1 && !isRed {
-		delta = kTileSizes[tile]
+		delta = b.rules.TileSizes[tile]
 		if isRed {
 			if curScore == 0 {
 				b.redScore += delta
@@ -335,24 +360,32 @@ func (b *KulamiBoard) Move(c Coord, isRed bool) error {
 	} else {
 		b.tileScore[tile] -= 1
 	}
-	return nil
+	b.updateGroup(isRed)
 }
 
 // UndoLastMove removes the last move from the board, if possible.
 func (b *KulamiBoard) UndoLastMove() {
-	numMoves := b.NumMoves()
-	if numMoves == 0 {
+	if b.numMoves == 0 {
 		return
 	}
-	c := b.moves[numMoves-1]
-	b.moves = b.moves[0 : numMoves-1]
-	isRed := b.marbles[c.Row][c.Col] == kRedMarble
-	b.marbles[c.Row][c.Col] = kEmptySpace
-	tile := b.tiles[c.Row][c.Col]
+	i := int(b.moves[b.numMoves-1])
+	isRed := b.red.has(i)
+	b.hash ^= b.turnHash() ^ marbleHash(i, isRed)
+	b.numMoves--
+	if b.setup > b.numMoves {
+		b.setup = b.numMoves
+	}
+	b.hash ^= b.turnHash()
+	if isRed {
+		b.red.clear(i)
+	} else {
+		b.black.clear(i)
+	}
+	tile := int(b.tiles[i])
 	curScore := b.tileScore[tile]
 	delta := 0
 	if curScore == 0 || curScore == 1 && isRed || curScore == -1 && !isRed {
-		delta = kTileSizes[tile]
+		delta = b.rules.TileSizes[tile]
 		if isRed {
 			if curScore == 0 {
 				b.blackScore += delta
@@ -372,44 +405,52 @@ func (b *KulamiBoard) UndoLastMove() {
 	} else {
 		b.tileScore[tile] += 1
 	}
+	b.updateGroup(isRed)
 }
 
-// LegalMoves returns all legal move candidates for the next move.
-func (b *KulamiBoard) LegalMoves() []Coord {
-	var res []Coord
-	numMoves := b.NumMoves()
-	if numMoves == 0 {
-		// All moves on the board are legal as a first move.
-		for row, cols := range b.marbles {
-			for col, v := range cols {
-				if v == kEmptySpace {
-					res = append(res, Coord{Row: row, Col: col})
-				}
-			}
-		}
-		return res
+// updateGroup recomputes the largest group of the given player after a change,
+// if groups are scored.
+func (b *KulamiBoard) updateGroup(isRed bool) {
+	if b.scoring != GroupScoring {
+		return
 	}
-	last := b.moves[numMoves-1]
-	if len(b.moves) == kNumMarbles*2 {
-		return res // Game over, out of marbles.
+	if isRed {
+		b.redGroup = b.red.largestGroup()
+	} else {
+		b.blackGroup = b.black.largestGroup()
 	}
-	prev := Coord{Row: -1, Col: -1}
-	if numMoves > 1 {
-		prev = b.moves[numMoves-2]
+}
+
+// legalMask returns the set of cells that are legal for the next move.
+func (b *KulamiBoard) legalMask() bitset {
+	if b.numMoves == b.maxMoves {
+		return bitset{} // Game over, out of marbles.
 	}
-	for row := 0; row <= b.end.Row; row++ {
-		if b.marbles[row][last.Col] == kEmptySpace &&
-			b.tiles[row][last.Col] != b.tiles[last.Row][last.Col] &&
-			(prev.Row == -1 || b.tiles[row][last.Col] != b.tiles[prev.Row][prev.Col]) {
-			res = append(res, Coord{Row: row, Col: last.Col})
-		}
+	res := b.holes.andNot(b.red).andNot(b.black)
+	if b.numMoves == 0 {
+		// All moves on the board are legal as a first move.
+		return res
 	}
-	for col := 0; col <= b.end.Col; col++ {
-		if b.marbles[last.Row][col] == kEmptySpace &&
-			b.tiles[last.Row][col] != b.tiles[last.Row][last.Col] &&
-			(prev.Row == -1 || b.tiles[last.Row][col] != b.tiles[prev.Row][prev.Col]) {
-			res = append(res, Coord{Row: last.Row, Col: col})
-		}
+	if b.rules.RowColumn {
+		c := cellCoord(int(b.moves[b.numMoves-1]))
+		res = res.and(rowMasks[c.Row].or(colMasks[c.Col]))
+	}
+	for k := 1; k <= b.rules.BlockedMoves && k <= b.numMoves; k++ {
+		res = res.andNot(b.tileMasks[b.tiles[b.moves[b.numMoves-k]]])
 	}
 	return res
 }
+
+// LegalMoves returns all legal move candidates for the next move. Under the
+// row/column rule, these are first the ones in the column of the last move,
+// then the ones in its row; otherwise they are in row-major order.
+func (b *KulamiBoard) LegalMoves() []Coord {
+	legal := b.legalMask()
+	if b.numMoves == 0 || !b.rules.RowColumn {
+		return legal.appendCoords(nil)
+	}
+	last := cellCoord(int(b.moves[b.numMoves-1]))
+	res := make([]Coord, 0, legal.count())
+	res = legal.and(colMasks[last.Col]).appendCoords(res)
+	return legal.and(rowMasks[last.Row]).appendCoords(res)
+}
@@ -1,6 +1,8 @@
 package board
 
 import (
+	"errors"
+	"math/rand"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -46,25 +48,25 @@ var sampleMoves = []Coord{
 }
 
 const printOut = `
-*     0   1   2   3   4   5   6   7   8   9  10
+*     a   b   c   d   e   f   g   h   i   j   k
                     ---------                   
-0                   | .   . |                   
+1                   | .   . |                   
         ------------|       ---------           
-1       | .   .   . | .   . | X   . |           
+2       | .   .   . | .   . | X   . |           
     ------------------------|       -----       
-2   | x | . | .   . | .   . | o   o | . |       
+3   | x | . | .   . | .   . | o   o | . |       
     |   |   |       |       |       |   |       
-3   | . | . | .   . | .   . | .   . | . |       
+4   | . | . | .   . | .   . | .   . | . |       
     --------------------------------|   ---------
-4   | o   x | . | o   .   x | o   x | . | .   . |
+5   | o   x | . | o   .   x | o   x | . | .   . |
     |       |   |           |       -------------
-5   | .   . | . | .   .   . | O   . | .   .   . |
+6   | .   . | . | .   .   . | O   . | .   .   . |
     |       -------------------------------------
-6   | .   . | .   .   . | .   x   . |           
+7   | .   . | .   .   . | .   x   . |           
     --------|           -------------           
-7           | .   .   . | .   x |               
+8           | .   .   . | .   x |               
             ------------|       |               
-8                       | .   . |               
+9                       | .   . |               
                         ---------               
 
 Score:		Red: 9	Black: 10
@@ -125,6 +127,100 @@ func TestMoveErrors(t *testing.T) {
 	}
 }
 
+func TestMoveErrorReasons(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	if err := b.Move(Coord{Row: 4, Col: 5}, true); err != nil {
+		t.Fatalf("Move(f5) failed: %v", err)
+	}
+	if err := b.Move(Coord{Row: 4, Col: 0}, false); err != nil {
+		t.Fatalf("Move(a5) failed: %v", err)
+	}
+	tests := []struct {
+		move    Coord
+		isRed   bool
+		wantErr error
+	}{
+		{Coord{Row: 2, Col: 0}, false, ErrNotYourTurn},
+		{Coord{Row: 4, Col: 5}, true, ErrOccupied},
+		{Coord{Row: 7, Col: 0}, true, ErrOutOfBounds},
+		{Coord{Row: 4, Col: 11}, true, ErrOutOfBounds},
+		{Coord{Row: -1, Col: 0}, true, ErrOutOfBounds},
+		{Coord{Row: 2, Col: 4}, true, ErrWrongLine},
+		{Coord{Row: 5, Col: 0}, true, ErrBlockedByOpponent},
+		{Coord{Row: 4, Col: 3}, true, ErrBlockedBySelf},
+	}
+	hash := b.Hash()
+	for _, tc := range tests {
+		err := b.ValidateMove(tc.move, tc.isRed)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("ValidateMove(%v, %v) = %v, want %v", tc.move, tc.isRed, err, tc.wantErr)
+			continue
+		}
+		var me *MoveError
+		if !errors.As(err, &me) || me.Coord != tc.move || me.IsRed != tc.isRed {
+			t.Errorf("ValidateMove(%v, %v) = %#v, want a *MoveError for the move", tc.move, tc.isRed, err)
+		}
+		if err := b.Move(tc.move, tc.isRed); !errors.Is(err, tc.wantErr) {
+			t.Errorf("Move(%v, %v) = %v, want %v", tc.move, tc.isRed, err, tc.wantErr)
+		}
+	}
+	if b.Hash() != hash || b.NumMoves() != 2 {
+		t.Errorf("illegal moves changed the board")
+	}
+	if err := b.ValidateMove(Coord{Row: 4, Col: 2}, true); err != nil {
+		t.Errorf("ValidateMove(4,2) = %v, want nil", err)
+	}
+	if b.NumMoves() != 2 {
+		t.Errorf("ValidateMove() changed the board")
+	}
+}
+
+func TestMoveGameOver(t *testing.T) {
+	locs := []TileLocation{
+		{Coord: Coord{Row: 0, Col: 0}},
+		{Coord: Coord{Row: 0, Col: 1}},
+	}
+	tests := []struct {
+		name  string
+		rules Rules
+		moves []Coord
+	}{
+		{
+			name:  "out of marbles",
+			rules: Rules{TileSizes: []int{2, 2}, MarblesPerPlayer: 2, BlockedMoves: 1},
+			moves: []Coord{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 1, Col: 1}},
+		},
+		{
+			name:  "no legal moves",
+			rules: Rules{TileSizes: []int{2, 1}, MarblesPerPlayer: 2, BlockedMoves: 1},
+			moves: []Coord{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 0}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewWithRules(locs, tc.rules)
+			if err != nil {
+				t.Fatalf("Error initializing board: %v", err)
+			}
+			isRed := true
+			for _, m := range tc.moves {
+				if err := b.Move(m, isRed); err != nil {
+					t.Fatalf("Move(%v) failed: %v", m, err)
+				}
+				isRed = !isRed
+			}
+			for _, m := range []Coord{{Row: 0, Col: 0}, {Row: 1, Col: 1}, {Row: 5, Col: 5}} {
+				if err := b.ValidateMove(m, isRed); !errors.Is(err, ErrGameOver) {
+					t.Errorf("ValidateMove(%v) = %v, want %v", m, err, ErrGameOver)
+				}
+			}
+		})
+	}
+}
+
 func TestLegalMoves(t *testing.T) {
 	b, err := New(sampleTiles)
 	if err != nil {
@@ -167,3 +263,241 @@ func TestString(t *testing.T) {
 		t.Errorf("String() returned:\n%s\nExpected:\n%s\n", got, printOut)
 	}
 }
+
+func benchmarkBoard(b *testing.B) *KulamiBoard {
+	board, err := New(sampleTiles)
+	if err != nil {
+		b.Fatalf("Error initializing board: %v", err)
+	}
+	isRed := true
+	for _, m := range sampleMoves {
+		if err := board.Move(m, isRed); err != nil {
+			b.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+		isRed = !isRed
+	}
+	return board
+}
+
+var cloneSink *KulamiBoard
+
+func BenchmarkClone(b *testing.B) {
+	board := benchmarkBoard(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cloneSink = board.Clone()
+	}
+}
+
+func BenchmarkMoveUndo(b *testing.B) {
+	board := benchmarkBoard(b)
+	moves := board.LegalMoves()
+	isRed := board.IsRedsTurn()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := moves[i%len(moves)]
+		if err := board.Move(m, isRed); err != nil {
+			b.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+		board.UndoLastMove()
+	}
+}
+
+func BenchmarkLegalMoves(b *testing.B) {
+	board := benchmarkBoard(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.LegalMoves()
+	}
+}
+
+// BenchmarkPlayout plays random games to the end from an empty board, the
+// typical inner loop of a searching AI.
+func BenchmarkPlayout(b *testing.B) {
+	start, err := New(sampleTiles)
+	if err != nil {
+		b.Fatalf("Error initializing board: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board := start.Clone()
+		for moves := board.LegalMoves(); len(moves) > 0; moves = board.LegalMoves() {
+			if err := board.Move(moves[rng.Intn(len(moves))], board.IsRedsTurn()); err != nil {
+				b.Fatalf("Move: %v", err)
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	seen := map[uint64]int{b.Hash(): 0}
+	var hashes []uint64
+	isRed := true
+	for i, m := range sampleMoves {
+		hashes = append(hashes, b.Hash())
+		if err := b.Move(m, isRed); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+		isRed = !isRed
+		if got, want := b.Hash(), b.ComputeHash(); got != want {
+			t.Errorf("after move %d: Hash() = %x, ComputeHash() = %x", i+1, got, want)
+		}
+		if j, ok := seen[b.Hash()]; ok {
+			t.Errorf("after move %d: Hash() = %x, same as after move %d", i+1, b.Hash(), j)
+		}
+		seen[b.Hash()] = i + 1
+	}
+	if got, want := b.Clone().Hash(), b.Hash(); got != want {
+		t.Errorf("Clone().Hash() = %x, want %x", got, want)
+	}
+	for i := len(sampleMoves) - 1; i >= 0; i-- {
+		b.UndoLastMove()
+		if got, want := b.Hash(), hashes[i]; got != want {
+			t.Errorf("after undoing move %d: Hash() = %x, want %x", i+1, got, want)
+		}
+	}
+}
+
+func TestHashTurnState(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	// The same marbles, but a different side to move and different blocked
+	// tiles, should hash differently.
+	red, black := b.Clone(), b.Clone()
+	if err := red.Move(Coord{Row: 4, Col: 5}, true); err != nil {
+		t.Fatalf("Move(4,5): %v", err)
+	}
+	if err := black.Move(Coord{Row: 4, Col: 5}, false); err != nil {
+		t.Fatalf("Move(4,5): %v", err)
+	}
+	if red.Hash() == black.Hash() {
+		t.Errorf("Hash() is the same for a red and a black marble on 4,5")
+	}
+	// Same marbles, but in reverse order.
+	b1, b2 := b.Clone(), b.Clone()
+	for _, m := range []Coord{{Row: 4, Col: 5}, {Row: 4, Col: 0}, {Row: 2, Col: 0}} {
+		if err := b1.Move(m, b1.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+	}
+	for _, m := range []Coord{{Row: 2, Col: 0}, {Row: 4, Col: 0}, {Row: 4, Col: 5}} {
+		if err := b2.Move(m, b2.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+	}
+	if b1.Hash() == b2.Hash() {
+		t.Errorf("Hash() is the same for positions with different last moves")
+	}
+}
+
+func TestGroupScoring(t *testing.T) {
+	b, err := New(sampleTiles, WithScoring(GroupScoring))
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	isRed := true
+	for _, m := range sampleMoves {
+		if err := b.Move(m, isRed); err != nil {
+			t.Errorf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+		isRed = !isRed
+	}
+	// Red: 6,6 and 7,6 are connected. Black: 2,6-2,7 and 4,6-5,6.
+	for _, tc := range []struct {
+		name      string
+		got, want int
+	}{
+		{"RedTileScore()", b.RedTileScore(), 9},
+		{"BlackTileScore()", b.BlackTileScore(), 10},
+		{"RedGroupScore()", b.RedGroupScore(), 2},
+		{"BlackGroupScore()", b.BlackGroupScore(), 2},
+		{"RedScore()", b.RedScore(), 11},
+		{"BlackScore()", b.BlackScore(), 12},
+		{"ScoreDiff(true)", b.ScoreDiff(true), -1},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %d, want %d", tc.name, tc.got, tc.want)
+		}
+	}
+	for range sampleMoves {
+		b.UndoLastMove()
+	}
+	if got, want := b.RedScore()+b.BlackScore(), 0; got != want {
+		t.Errorf("RedScore()+BlackScore() = %d, want %d", got, want)
+	}
+}
+
+func TestGroupScoringGrowth(t *testing.T) {
+	b, err := New(sampleTiles, WithScoring(GroupScoring))
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	tests := []struct {
+		move             Coord
+		wantRed, wantBlk int
+	}{
+		{Coord{Row: 4, Col: 0}, 1, 0},
+		{Coord{Row: 4, Col: 3}, 1, 1},
+		{Coord{Row: 4, Col: 2}, 1, 1},
+		{Coord{Row: 6, Col: 2}, 1, 1},
+		{Coord{Row: 3, Col: 2}, 2, 1},
+		{Coord{Row: 3, Col: 0}, 2, 1},
+		{Coord{Row: 5, Col: 0}, 2, 1},
+	}
+	for i, tc := range tests {
+		if err := b.Move(tc.move, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", tc.move.Row, tc.move.Col, err)
+		}
+		if got := b.RedGroupScore(); got != tc.wantRed {
+			t.Errorf("after move %d: RedGroupScore() = %d, want %d", i+1, got, tc.wantRed)
+		}
+		if got := b.BlackGroupScore(); got != tc.wantBlk {
+			t.Errorf("after move %d: BlackGroupScore() = %d, want %d", i+1, got, tc.wantBlk)
+		}
+	}
+	c := b.Clone()
+	for range tests {
+		c.UndoLastMove()
+	}
+	if c.RedGroupScore() != 0 || c.BlackGroupScore() != 0 {
+		t.Errorf("group scores after undoing all moves = %d, %d, want 0, 0", c.RedGroupScore(), c.BlackGroupScore())
+	}
+	if got, want := b.RedGroupScore(), 2; got != want {
+		t.Errorf("RedGroupScore() of the original after undoing the clone = %d, want %d", got, want)
+	}
+}
+
+func TestLargestGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells []Coord
+		want  int
+	}{
+		{"empty", nil, 0},
+		{"single", []Coord{{Row: 3, Col: 3}}, 1},
+		{"diagonal", []Coord{{Row: 3, Col: 3}, {Row: 4, Col: 4}}, 1},
+		{"row wrap", []Coord{{Row: 0, Col: kMaxCols - 1}, {Row: 1, Col: 0}}, 1},
+		{"word boundary", []Coord{{Row: 3, Col: 5}, {Row: 4, Col: 5}, {Row: 4, Col: 6}}, 3},
+		{"two groups", []Coord{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 5, Col: 5}, {Row: 6, Col: 5}, {Row: 6, Col: 4}}, 3},
+	}
+	for _, tc := range tests {
+		var s bitset
+		for _, c := range tc.cells {
+			s.set(cellIndex(c.Row, c.Col))
+		}
+		if got := s.largestGroup(); got != tc.want {
+			t.Errorf("%s: largestGroup() = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
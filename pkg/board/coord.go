@@ -0,0 +1,44 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// String returns the coordinate in algebraic notation: a letter for the
+// column, starting with a, and the row number, starting with 1 at the top.
+// For example, Coord{Row: 3, Col: 2} is "c4". Coordinates outside the
+// largest supported board are written in the legacy "row,col" form.
+func (c Coord) String() string {
+	if c.Row < 0 || c.Row >= kMaxRows || c.Col < 0 || c.Col >= kMaxCols {
+		return fmt.Sprintf("%d,%d", c.Row, c.Col)
+	}
+	return fmt.Sprintf("%c%d", 'a'+c.Col, c.Row+1)
+}
+
+// ParseCoord parses a coordinate in the algebraic notation of Coord.String,
+// e.g. "c4", or in the legacy "row,col" form, e.g. "3,2".
+func ParseCoord(s string) (Coord, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ","); i >= 0 {
+		row, err := strconv.Atoi(strings.TrimSpace(s[:i]))
+		if err != nil {
+			return Coord{}, fmt.Errorf("bad row in %q: %v", s, err)
+		}
+		col, err := strconv.Atoi(strings.TrimSpace(s[i+1:]))
+		if err != nil {
+			return Coord{}, fmt.Errorf("bad column in %q: %v", s, err)
+		}
+		return Coord{Row: row, Col: col}, nil
+	}
+	if len(s) < 2 {
+		return Coord{}, fmt.Errorf("bad coordinate %q, expected a column letter and a row number such as c4", s)
+	}
+	col := int(strings.ToLower(s[:1])[0] - 'a')
+	row, err := strconv.Atoi(s[1:])
+	if col < 0 || col >= kMaxCols || err != nil || row < 1 || row > kMaxRows || s[1] == '+' {
+		return Coord{}, fmt.Errorf("bad coordinate %q, expected a column letter from a to %c and a row number from 1 to %d", s, 'a'+kMaxCols-1, kMaxRows)
+	}
+	return Coord{Row: row - 1, Col: col}, nil
+}
@@ -0,0 +1,55 @@
+package board
+
+import "testing"
+
+func TestCoordString(t *testing.T) {
+	tests := []struct {
+		c    Coord
+		want string
+	}{
+		{Coord{Row: 0, Col: 0}, "a1"},
+		{Coord{Row: 3, Col: 2}, "c4"},
+		{Coord{Row: 9, Col: 10}, "k10"},
+		{Coord{Row: 15, Col: 15}, "p16"},
+		{Coord{Row: -1, Col: 2}, "-1,2"},
+		{Coord{Row: 3, Col: 16}, "3,16"},
+	}
+	for _, tc := range tests {
+		if got := tc.c.String(); got != tc.want {
+			t.Errorf("%#v.String() = %q, want %q", tc.c, got, tc.want)
+		}
+	}
+}
+
+func TestParseCoord(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Coord
+	}{
+		{"a1", Coord{Row: 0, Col: 0}},
+		{"c4", Coord{Row: 3, Col: 2}},
+		{"C4", Coord{Row: 3, Col: 2}},
+		{" k10 ", Coord{Row: 9, Col: 10}},
+		{"p16", Coord{Row: 15, Col: 15}},
+		{"3,2", Coord{Row: 3, Col: 2}},
+		{"3, 2", Coord{Row: 3, Col: 2}},
+	}
+	for _, tc := range tests {
+		got, err := ParseCoord(tc.s)
+		if err != nil {
+			t.Errorf("ParseCoord(%q) failed: %v", tc.s, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseCoord(%q) = %#v, want %#v", tc.s, got, tc.want)
+		}
+		if again, err := ParseCoord(got.String()); err != nil || again != got {
+			t.Errorf("ParseCoord(%q) = %#v, %v, want %#v", got.String(), again, err, got)
+		}
+	}
+	for _, s := range []string{"", "c", "z1", "a0", "a17", "c+4", "4c", "x,y", "3,"} {
+		if got, err := ParseCoord(s); err == nil {
+			t.Errorf("ParseCoord(%q) = %v, want an error", s, got)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiagramPart is the part of a board diagram a piece of its text belongs to.
+type DiagramPart int
+
+const (
+	// DiagramColumnLabel is the letter of column Coord.Col.
+	DiagramColumnLabel DiagramPart = iota
+	// DiagramRowLabel is the number of row Coord.Row, padded to 4 characters.
+	DiagramRowLabel
+	// DiagramBorder is a piece of the line above row Coord.Row: the edges of
+	// tiles, with - and |, or the spaces after them. Coord.Row is Rows() for
+	// the line below the board, and Coord.Col is Cols() for the end of a line.
+	DiagramBorder
+	// DiagramSeparator is the | or space before hole Coord in its row, or the
+	// | at the end of the row if Coord.Col is Cols().
+	DiagramSeparator
+	// DiagramHole is hole Coord with a space on each side: ., x or o, in
+	// capitals for the last two moves, or a space outside of the tiles.
+	DiagramHole
+)
+
+// DiagramPiece is a piece of the text of a board diagram.
+type DiagramPiece struct {
+	Part  DiagramPart
+	Coord Coord
+	Text  string
+}
+
+// Diagram returns the board in the format of String, with every piece of the
+// board passed through paint, which returns the text to write for it, such as
+// the piece in color. Paint may be nil, for String itself.
+func (b *KulamiBoard) Diagram(paint func(DiagramPiece) string) string {
+	var res strings.Builder
+	put := func(part DiagramPart, row, col int, s string) {
+		if paint != nil {
+			s = paint(DiagramPiece{Part: part, Coord: Coord{Row: row, Col: col}, Text: s})
+		}
+		res.WriteString(s)
+	}
+	rows, cols := b.end.Row+1, b.end.Col+1
+	m1, m2 := b.lastMoves()
+	// Print column letters.
+	res.WriteString("*  ")
+	for col := 0; col < cols; col++ {
+		res.WriteString("   ")
+		put(DiagramColumnLabel, 0, col, string(rune('a'+col)))
+	}
+	res.WriteString("\n")
+	for row := 0; row < rows; row++ {
+		// A row of separators between rows.
+		res.WriteString("    ")
+		for col := 0; col < cols; col++ {
+			t := b.tileAt(row, col)
+			switch {
+			case row == 0 && t != kOutOfBounds || row != 0 && t != b.tileAt(row-1, col):
+				put(DiagramBorder, row, col, "----")
+			case col == 0 && t != kOutOfBounds || col != 0 && t != b.tileAt(row, col-1):
+				if t == kOutOfBounds && (row == 0 || b.tileAt(row-1, col-1) == kOutOfBounds) {
+					put(DiagramBorder, row, col, "-")
+				} else {
+					put(DiagramBorder, row, col, "|")
+				}
+				put(DiagramBorder, row, col, "   ")
+			case col != 0 && row != 0 && t == kOutOfBounds && b.tileAt(row-1, col-1) != kOutOfBounds:
+				put(DiagramBorder, row, col, "-")
+				put(DiagramBorder, row, col, "   ")
+			default:
+				put(DiagramBorder, row, col, "    ")
+			}
+		}
+		// Close the row from the right with either | or -.
+		if b.tileAt(row, b.end.Col) != kOutOfBounds || row != 0 && b.tileAt(row-1, b.end.Col) != kOutOfBounds {
+			// | is only for if we're inside the same tile.
+			if row != 0 && b.tileAt(row, b.end.Col) == b.tileAt(row-1, b.end.Col) {
+				put(DiagramBorder, row, cols, "|")
+			} else {
+				put(DiagramBorder, row, cols, "-")
+			}
+		}
+		res.WriteString("\n")
+		// Print row number.
+		put(DiagramRowLabel, row, 0, fmt.Sprintf("%-4d", row+1))
+		// Print actual content (including marbles).
+		for col := 0; col < cols; col++ {
+			isLast := (row == m1.Row && col == m1.Col || row == m2.Row && col == m2.Col)
+			m := " "
+			switch b.marbleAt(row, col) {
+			case kEmptySpace:
+				m = "."
+			case kRedMarble:
+				m = "x"
+				if isLast {
+					m = "X"
+				}
+			case kBlackMarble:
+				m = "o"
+				if isLast {
+					m = "O"
+				}
+			}
+			if col == 0 && b.tileAt(row, 0) != kOutOfBounds || col != 0 && b.tileAt(row, col) != b.tileAt(row, col-1) {
+				put(DiagramSeparator, row, col, "|")
+			} else {
+				put(DiagramSeparator, row, col, " ")
+			}
+			put(DiagramHole, row, col, " "+m+" ")
+		}
+		// Possibly close the row from the right with |.
+		if b.tileAt(row, b.end.Col) != kOutOfBounds {
+			put(DiagramSeparator, row, cols, "|")
+		}
+		res.WriteString("\n")
+	}
+	// Last closing row.
+	res.WriteString("    ")
+	for col := 0; col < cols; col++ {
+		if b.tileAt(b.end.Row, col) != kOutOfBounds {
+			put(DiagramBorder, rows, col, "----")
+		} else if col != 0 && b.tileAt(b.end.Row, col-1) != kOutOfBounds {
+			put(DiagramBorder, rows, col, "-")
+			put(DiagramBorder, rows, col, "   ")
+		} else {
+			put(DiagramBorder, rows, col, "    ")
+		}
+	}
+	res.WriteString("\n")
+	fmt.Fprintf(&res, "\nScore:\t\tRed: %d\tBlack: %d\n", b.RedScore(), b.BlackScore())
+	if b.scoring == GroupScoring {
+		fmt.Fprintf(&res, "Groups:\t\tRed: %d\tBlack: %d\n", b.redGroup, b.blackGroup)
+	}
+	return res.String()
+}
+
+// kTileShapes maps the height and width of a tile to its size and whether it
+// is landscape.
+var kTileShapes = map[[2]int]TileLocation{
+	{1, 1}: {},
+	{2, 1}: {},
+	{1, 2}: {IsLandscape: true},
+	{3, 1}: {},
+	{1, 3}: {IsLandscape: true},
+	{2, 2}: {},
+	{3, 2}: {},
+	{2, 3}: {IsLandscape: true},
+}
+
+// ParseDiagram rebuilds a position from a board diagram in the format of
+// KulamiBoard.String. The diagram may be indented, and anything after the
+// board, such as the score, is ignored.
+//
+// The diagram shows the tiles and marbles, and the last two moves in capital
+// letters, but not the order of the other moves. So the board has all its
+// marbles placed as set-up moves (see SetupMoves), ending with the last two.
+// Its scores are those of the game it was printed from. So are its legal
+// moves and side to move, except that with as many black marbles as red
+// ones, the diagram does not tell who moved first: Red is assumed, unless
+// the BlackFirst option is given. Tiles are matched to the tile sizes of the
+// rules in row-major order of their upper left corners.
+func ParseDiagram(diagram string, r Rules, opts ...Option) (*KulamiBoard, error) {
+	lines := strings.Split(strings.ReplaceAll(diagram, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty diagram")
+	}
+	star := strings.Index(lines[0], "*")
+	if star < 0 || strings.TrimSpace(lines[0][:star]) != "" {
+		return nil, fmt.Errorf("diagram should start with a line of column letters after *")
+	}
+	indent := lines[0][:star]
+	for i := range lines {
+		lines[i] = strings.TrimPrefix(lines[i], indent)
+	}
+	cols := len(strings.Fields(lines[0])) - 1
+	// Rows alternate between a line of borders and a line of holes, which
+	// starts with the row index.
+	var borders, contents []string
+	for k := 1; k < len(lines); k += 2 {
+		borders = append(borders, lines[k])
+		if k+1 >= len(lines) || strings.TrimSpace(prefix(lines[k+1], 4)) == "" {
+			break
+		}
+		contents = append(contents, lines[k+1])
+	}
+	rows := len(contents)
+	if rows == 0 || cols <= 0 || rows > kMaxRows || cols > kMaxCols {
+		return nil, fmt.Errorf("diagram of %dx%d is not between 1x1 and %dx%d", rows, cols, kMaxRows, kMaxCols)
+	}
+
+	// Find the holes and the walls between tiles.
+	var holes bitset
+	content := make(map[int]byte)
+	for row, line := range contents {
+		for col := 0; col < cols; col++ {
+			sep, m := charAt(line, 4+4*col), charAt(line, 4+4*col+2)
+			if sep != ' ' && sep != '|' || charAt(line, 4+4*col+1) != ' ' || charAt(line, 4+4*col+3) != ' ' || !strings.ContainsRune(" .xoXO", rune(m)) {
+				return nil, fmt.Errorf("line %q: unexpected content of %v", line, Coord{Row: row, Col: col})
+			}
+			if m != ' ' {
+				holes.set(cellIndex(row, col))
+				content[cellIndex(row, col)] = m
+			}
+		}
+	}
+	sameTile := func(row, col, row2, col2 int) bool {
+		if !holes.has(cellIndex(row, col)) || !holes.has(cellIndex(row2, col2)) {
+			return false
+		}
+		if row == row2 {
+			return charAt(contents[row], 4+4*col2) != '|'
+		}
+		return charAt(borders[row2], 4+4*col+1) != '-'
+	}
+
+	// Collect the tiles in row-major order of their upper left corners.
+	var tiles []TileLocation
+	var sizes []int
+	var seen bitset
+	for i := holes.next(0); i >= 0; i = holes.next(i + 1) {
+		if seen.has(i) {
+			continue
+		}
+		var tile bitset
+		tile.set(i)
+		for queue := []int{i}; len(queue) > 0; queue = queue[1:] {
+			c := cellCoord(queue[0])
+			for _, n := range []Coord{{Row: c.Row - 1, Col: c.Col}, {Row: c.Row + 1, Col: c.Col}, {Row: c.Row, Col: c.Col - 1}, {Row: c.Row, Col: c.Col + 1}} {
+				if n.Row < 0 || n.Row >= rows || n.Col < 0 || n.Col >= cols || tile.has(cellIndex(n.Row, n.Col)) {
+					continue
+				}
+				if sameTile(minInt(c.Row, n.Row), minInt(c.Col, n.Col), maxInt(c.Row, n.Row), maxInt(c.Col, n.Col)) {
+					tile.set(cellIndex(n.Row, n.Col))
+					queue = append(queue, cellIndex(n.Row, n.Col))
+				}
+			}
+		}
+		seen = seen.or(tile)
+		start, end := cellCoord(i), cellCoord(i)
+		for _, c := range tile.appendCoords(nil) {
+			end.Row, end.Col = maxInt(end.Row, c.Row), maxInt(end.Col, c.Col)
+			start.Col = minInt(start.Col, c.Col)
+		}
+		h, w := end.Row-start.Row+1, end.Col-start.Col+1
+		shape, ok := kTileShapes[[2]int{h, w}]
+		if !ok || tile.count() != h*w {
+			return nil, fmt.Errorf("the tile at %v is not a rectangle of a supported size", start)
+		}
+		shape.Coord = start
+		tiles = append(tiles, shape)
+		sizes = append(sizes, h*w)
+	}
+
+	// Match the tiles to the rules.
+	if len(tiles) != len(r.TileSizes) {
+		return nil, fmt.Errorf("diagram has %d tiles, the rules have %d", len(tiles), len(r.TileSizes))
+	}
+	locs := make([]TileLocation, len(tiles))
+	used := make([]bool, len(tiles))
+	for t, size := range r.TileSizes {
+		found := false
+		for i := range tiles {
+			if !used[i] && sizes[i] == size {
+				locs[t], used[i], found = tiles[i], true, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("diagram has no tile of size %d for tile %d of the rules", size, t)
+		}
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	b, err := NewWithRules(locs, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Place the marbles, alternating colors and ending with the last moves.
+	var red, black, last []Coord
+	for i := holes.next(0); i >= 0; i = holes.next(i + 1) {
+		switch content[i] {
+		case 'x':
+			red = append(red, cellCoord(i))
+		case 'o':
+			black = append(black, cellCoord(i))
+		case 'X', 'O':
+			last = append(last, cellCoord(i))
+		}
+	}
+	var lastRed, lastBlack []Coord
+	for _, c := range last {
+		if content[cellIndex(c.Row, c.Col)] == 'X' {
+			lastRed = append(lastRed, c)
+		} else {
+			lastBlack = append(lastBlack, c)
+		}
+	}
+	numRed, numBlack := len(red)+len(lastRed), len(black)+len(lastBlack)
+	n := numRed + numBlack
+	if numRed > numBlack+1 || numBlack > numRed+1 {
+		return nil, fmt.Errorf("diagram has %d red and %d black marbles, which is not a game position", numRed, numBlack)
+	}
+	if len(lastRed) > 1 || len(lastBlack) > 1 || len(last) != minInt(n, 2) {
+		return nil, fmt.Errorf("diagram should mark the last two moves with one X and one O")
+	}
+	// Red moves first unless there are more black marbles, or as many and
+	// the caller says so.
+	redLast := numRed > numBlack || numRed == numBlack && n > 0 && o.blackFirst
+	moves := make([]Coord, 0, n)
+	for k := 0; k < n; k++ {
+		isRed := redLast == ((n-1-k)%2 == 0)
+		switch {
+		case k >= n-2 && isRed:
+			moves = append(moves, lastRed[0])
+		case k >= n-2:
+			moves = append(moves, lastBlack[0])
+		case isRed:
+			moves = append(moves, red[0])
+			red = red[1:]
+		default:
+			moves = append(moves, black[0])
+			black = black[1:]
+		}
+	}
+	isRed := redLast == (n%2 == 1)
+	for _, m := range moves {
+		if err := b.placeSetup(m, isRed); err != nil {
+			return nil, err
+		}
+		isRed = !isRed
+	}
+	return b, nil
+}
+
+// prefix returns the first n bytes of s, or all of s if it is shorter.
+func prefix(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// charAt returns the byte at index i of s, padding s with spaces.
+func charAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return ' '
+}
@@ -0,0 +1,294 @@
+package board
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestDiagram(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	counts := make(map[DiagramPart]int)
+	got := b.Diagram(func(p DiagramPiece) string {
+		counts[p.Part]++
+		if p.Part == DiagramHole && b.MarbleAt(p.Coord) == EmptyHole {
+			return " + "
+		}
+		return p.Text
+	})
+	if want := strings.ReplaceAll(b.String(), " . ", " + "); got != want {
+		t.Errorf("Diagram() returned:\n%s\nExpected:\n%s\n", got, want)
+	}
+	if b.Diagram(nil) != b.String() {
+		t.Errorf("Diagram(nil) differs from String()")
+	}
+	if counts[DiagramColumnLabel] != b.Cols() || counts[DiagramRowLabel] != b.Rows() || counts[DiagramHole] != b.Rows()*b.Cols() {
+		t.Errorf("Diagram() painted %v, want a label for each of %d columns and %d rows and a hole for each cell", counts, b.Cols(), b.Rows())
+	}
+}
+
+func TestParseDiagram(t *testing.T) {
+	want, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves {
+		if err := want.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	b, err := ParseDiagram(printOut, DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if got := b.String(); "\n"+got != printOut {
+		t.Errorf("String() of the parsed board returned:\n%s\nExpected:\n%s\n", got, printOut)
+	}
+	byCoord := cmpopts.SortSlices(func(a, b TileLocation) bool {
+		return a.Coord.Row < b.Coord.Row || a.Coord.Row == b.Coord.Row && a.Coord.Col < b.Coord.Col
+	})
+	if diff := cmp.Diff(want.Layout(), b.Layout(), byCoord); diff != "" {
+		t.Errorf("Layout() returned diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.LegalMoves(), b.LegalMoves()); diff != "" {
+		t.Errorf("LegalMoves() returned diff (-want +got):\n%s", diff)
+	}
+	if b.IsRedsTurn() != want.IsRedsTurn() || b.RedScore() != want.RedScore() || b.BlackScore() != want.BlackScore() {
+		t.Errorf("ParseDiagram() = turn %v, scores %d-%d, want turn %v, scores %d-%d", b.IsRedsTurn(), b.RedScore(), b.BlackScore(), want.IsRedsTurn(), want.RedScore(), want.BlackScore())
+	}
+	if b.Hash() != b.ComputeHash() {
+		t.Errorf("Hash() = %x, want %x", b.Hash(), b.ComputeHash())
+	}
+	if b.SetupMoves() != len(sampleMoves) {
+		t.Errorf("SetupMoves() = %d, want %d", b.SetupMoves(), len(sampleMoves))
+	}
+	// The game goes on from the set-up position.
+	m := want.LegalMoves()[0]
+	if err := b.Move(m, want.IsRedsTurn()); err != nil {
+		t.Fatalf("Move(%v) failed: %v", m, err)
+	}
+	if err := want.Move(m, !b.IsRedsTurn()); err != nil {
+		t.Fatalf("Move(%v) failed: %v", m, err)
+	}
+	if diff := cmp.Diff(want.LegalMoves(), b.LegalMoves()); diff != "" {
+		t.Errorf("LegalMoves() after Move() returned diff (-want +got):\n%s", diff)
+	}
+	if b.SetupMoves() != len(sampleMoves) {
+		t.Errorf("SetupMoves() after Move() = %d, want %d", b.SetupMoves(), len(sampleMoves))
+	}
+	// Undoing a set-up move makes it no longer part of the set-up.
+	b.UndoLastMove()
+	b.UndoLastMove()
+	if b.SetupMoves() != len(sampleMoves)-1 {
+		t.Errorf("SetupMoves() after UndoLastMove() = %d, want %d", b.SetupMoves(), len(sampleMoves)-1)
+	}
+}
+
+func TestParseDiagramBlackFirst(t *testing.T) {
+	want, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves[:4] {
+		if err := want.Move(m, i%2 == 1); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	// With as many marbles of each color, Red is assumed to have moved first.
+	b, err := ParseDiagram(want.String(), DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if !b.IsRedsTurn() {
+		t.Errorf("ParseDiagram() without BlackFirst = Black's turn, want Red's")
+	}
+	b, err = ParseDiagram(want.String(), DefaultRules(), BlackFirst())
+	if err != nil {
+		t.Fatalf("ParseDiagram(BlackFirst) failed: %v", err)
+	}
+	if got := b.String(); got != want.String() {
+		t.Errorf("String() of the parsed board returned:\n%s\nExpected:\n%s\n", got, want.String())
+	}
+	if b.IsRedsTurn() != want.IsRedsTurn() {
+		t.Errorf("ParseDiagram(BlackFirst) = turn %v, want %v", b.IsRedsTurn(), want.IsRedsTurn())
+	}
+	if diff := cmp.Diff(want.LegalMoves(), b.LegalMoves()); diff != "" {
+		t.Errorf("LegalMoves() returned diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseDiagramIndented(t *testing.T) {
+	var indented []string
+	for _, line := range strings.Split(printOut, "\n") {
+		indented = append(indented, strings.TrimRight("\t\t"+line, " \t"))
+	}
+	b, err := ParseDiagram(strings.Join(indented, "\n"), DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if got := b.String(); "\n"+got != printOut {
+		t.Errorf("String() of the parsed board returned:\n%s\nExpected:\n%s\n", got, printOut)
+	}
+}
+
+func TestParseDiagramLegacyLabels(t *testing.T) {
+	// Diagrams printed before algebraic notation have numeric column labels
+	// and rows counted from 0.
+	lines := strings.Split(printOut, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "*") {
+			lines[i] = "*     0   1   2   3   4   5   6   7   8   9  10"
+		} else if row, err := strconv.Atoi(strings.TrimSpace(prefix(line, 4))); err == nil {
+			lines[i] = fmt.Sprintf("%-4d", row-1) + line[4:]
+		}
+	}
+	b, err := ParseDiagram(strings.Join(lines, "\n"), DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if got := b.String(); "\n"+got != printOut {
+		t.Errorf("String() of the parsed board returned:\n%s\nExpected:\n%s\n", got, printOut)
+	}
+}
+
+func TestParseDiagramEmptyBoard(t *testing.T) {
+	want, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	b, err := ParseDiagram(want.String(), DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if b.NumMoves() != 0 || !b.IsRedsTurn() {
+		t.Errorf("ParseDiagram() = %d moves, red's turn %v, want an empty board", b.NumMoves(), b.IsRedsTurn())
+	}
+	if diff := cmp.Diff(want.LegalMoves(), b.LegalMoves()); diff != "" {
+		t.Errorf("LegalMoves() returned diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseDiagramErrors(t *testing.T) {
+	r := DefaultRules()
+	small := Rules{TileSizes: []int{4, 2}, MarblesPerPlayer: 3, BlockedMoves: 1, RowColumn: true}
+	tests := []struct {
+		name    string
+		diagram string
+		rules   Rules
+	}{
+		{name: "empty", diagram: "\n\n", rules: r},
+		{name: "no header", diagram: "Score: 1-2", rules: r},
+		{name: "no rows", diagram: "*     0   1\n", rules: r},
+		{name: "wrong tiles", diagram: printOut, rules: small},
+		{
+			name: "bad marble",
+			diagram: `
+*     0   1   2
+    -------------
+0   | .   . | . |
+    |       |   |
+1   | ?   . | . |
+    -------------
+`,
+			rules: small,
+		},
+		{
+			name: "not a rectangle",
+			diagram: `
+*     0   1   2
+    -------------
+0   | .   .   . |
+    |       -----
+1   | .   . |
+    ---------
+`,
+			rules: small,
+		},
+		{
+			name: "too many red marbles",
+			diagram: `
+*     0   1   2
+    -------------
+0   | x   X | . |
+    |       |   |
+1   | x   . | . |
+    -------------
+`,
+			rules: small,
+		},
+		{
+			name: "unmarked last move",
+			diagram: `
+*     0   1   2
+    -------------
+0   | x   o | . |
+    |       |   |
+1   | .   . | . |
+    -------------
+`,
+			rules: small,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if b, err := ParseDiagram(tc.diagram, tc.rules); err == nil {
+				t.Errorf("ParseDiagram() = %v, want error", b)
+			}
+		})
+	}
+}
+
+func TestSetupSerialization(t *testing.T) {
+	b, err := ParseDiagram(printOut, DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if err := b.Move(b.LegalMoves()[0], b.IsRedsTurn()); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	var fromJSON KulamiBoard
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	if fromJSON.String() != b.String() || fromJSON.SetupMoves() != b.SetupMoves() {
+		t.Errorf("JSON round trip returned:\n%s\n%d set-up moves, want:\n%s\n%d set-up moves", &fromJSON, fromJSON.SetupMoves(), b, b.SetupMoves())
+	}
+
+	var buf bytes.Buffer
+	if err := FormatRecord(&buf, NewRecord(b)); err != nil {
+		t.Fatalf("FormatRecord() failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `[Setup "13"]`) {
+		t.Errorf("FormatRecord() returned:\n%s\nwant a Setup header", buf.String())
+	}
+	rec, err := ParseRecord(&buf)
+	if err != nil {
+		t.Fatalf("ParseRecord() failed: %v", err)
+	}
+	fromRecord, err := rec.Board()
+	if err != nil {
+		t.Fatalf("Board() failed: %v", err)
+	}
+	if fromRecord.String() != b.String() || fromRecord.SetupMoves() != b.SetupMoves() {
+		t.Errorf("record round trip returned:\n%s\n%d set-up moves, want:\n%s\n%d set-up moves", fromRecord, fromRecord.SetupMoves(), b, b.SetupMoves())
+	}
+}
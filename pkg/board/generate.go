@@ -0,0 +1,240 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// kMaxGenerateAttempts bounds the random restarts of GenerateLayout.
+const kMaxGenerateAttempts = 10000
+
+// ErrNoLayout is returned by GenerateLayout if it fails to satisfy the
+// constraints.
+var ErrNoLayout = errors.New("no layout found")
+
+// LayoutConstraints restrict the layouts made by GenerateLayout.
+type LayoutConstraints struct {
+	// MaxRows and MaxCols bound the size of the layout. Zero means the
+	// 10x10 frame of the official game.
+	MaxRows, MaxCols int
+	// Connected requires the tiles to form a single edge-connected island.
+	Connected bool
+	// Symmetry the layout must have: Identity (none), Rotate180,
+	// FlipHorizontal or FlipVertical.
+	Symmetry Symmetry
+	// MinHolesPerLine is the minimal number of holes in every row and
+	// column of the layout.
+	MinHolesPerLine int
+}
+
+// OfficialConstraints returns the constraints of a physically legal layout
+// of the official game, see ValidateLayout.
+func OfficialConstraints() LayoutConstraints {
+	return LayoutConstraints{MaxRows: kFrameSize, MaxCols: kFrameSize, Connected: true}
+}
+
+// GenerateLayout returns a random layout of the tiles of the given rules
+// satisfying the constraints. The layout starts at 0,0 and only depends on
+// the state of rng, so a seeded rng reproduces it.
+func GenerateLayout(rng *rand.Rand, r Rules, c LayoutConstraints) ([]TileLocation, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	g := &generator{rng: rng, rules: r, c: c, rows: c.MaxRows, cols: c.MaxCols}
+	if g.rows == 0 {
+		g.rows = kFrameSize
+	}
+	if g.cols == 0 {
+		g.cols = kFrameSize
+	}
+	if g.rows < 0 || g.cols < 0 || g.rows > kMaxRows || g.cols > kMaxCols {
+		return nil, fmt.Errorf("layout size %dx%d is not between 1x1 and %dx%d", g.rows, g.cols, kMaxRows, kMaxCols)
+	}
+	switch c.Symmetry {
+	case Identity, Rotate180, FlipHorizontal, FlipVertical:
+	default:
+		return nil, fmt.Errorf("unsupported layout symmetry %v", c.Symmetry)
+	}
+	for i := 0; i < kMaxGenerateAttempts; i++ {
+		if locs := g.attempt(); locs != nil {
+			return locs, nil
+		}
+	}
+	return nil, fmt.Errorf("%w in %d attempts", ErrNoLayout, kMaxGenerateAttempts)
+}
+
+type generator struct {
+	rng        *rand.Rand
+	rules      Rules
+	c          LayoutConstraints
+	rows, cols int
+}
+
+// placement is a candidate location of a tile, and its mirror image if the
+// layout is symmetric.
+type placement struct {
+	loc, image TileLocation
+	cells      bitset
+	imageCells bitset
+}
+
+// attempt places all tiles at random, returning nil if it got stuck or the
+// result violates the constraints.
+func (g *generator) attempt() []TileLocation {
+	sizes := g.rules.TileSizes
+	n := len(sizes)
+	symmetric := g.c.Symmetry != Identity
+	counts := map[int]int{}
+	for _, size := range sizes {
+		counts[size]++
+	}
+	// Place large tiles first. A symmetric layout needs a self-symmetric tile
+	// of every size with an odd count, and those are easiest to place early.
+	order := g.rng.Perm(n)
+	sort.SliceStable(order, func(i, j int) bool {
+		si, sj := sizes[order[i]], sizes[order[j]]
+		if symmetric && counts[si]%2 != counts[sj]%2 {
+			return counts[si]%2 == 1
+		}
+		return si > sj
+	})
+	locs := make([]TileLocation, n)
+	placed := make([]bool, n)
+	var used bitset
+	for i, t := range order {
+		if placed[t] {
+			continue
+		}
+		size := sizes[t]
+		partner := -1
+		if symmetric && counts[size]%2 == 0 {
+			for _, o := range order[i+1:] {
+				if !placed[o] && sizes[o] == size {
+					partner = o
+					break
+				}
+			}
+		}
+		cands := g.candidates(size, used, symmetric, partner < 0)
+		if len(cands) == 0 {
+			return nil
+		}
+		p := cands[g.rng.Intn(len(cands))]
+		locs[t], placed[t] = p.loc, true
+		used = used.or(p.cells)
+		counts[size]--
+		if partner >= 0 {
+			locs[partner], placed[partner] = p.image, true
+			used = used.or(p.imageCells)
+			counts[size]--
+		}
+	}
+	return g.finish(locs)
+}
+
+// candidates returns all valid placements of a tile of the given size. For a
+// symmetric layout, the tile must either be its own image (self), or its
+// image must be free for the partner tile.
+func (g *generator) candidates(size int, used bitset, symmetric, self bool) []placement {
+	var res []placement
+	border := used.neighbors()
+	for row := 0; row < g.rows; row++ {
+		for col := 0; col < g.cols; col++ {
+			for _, landscape := range []bool{false, true} {
+				if landscape && (size == 1 || size == 4) {
+					continue // Same shape as portrait.
+				}
+				p := placement{loc: TileLocation{Coord: Coord{Row: row, Col: col}, IsLandscape: landscape}}
+				end := p.loc.tileEnd(size)
+				if end.Row >= g.rows || end.Col >= g.cols {
+					continue
+				}
+				p.cells = rectCells(p.loc.Coord, end)
+				if !p.cells.and(used).isEmpty() {
+					continue
+				}
+				if g.c.Connected && !used.isEmpty() && p.cells.and(border).isEmpty() {
+					continue
+				}
+				if symmetric {
+					a := g.c.Symmetry.Apply(p.loc.Coord, g.rows, g.cols)
+					b := g.c.Symmetry.Apply(end, g.rows, g.cols)
+					start := Coord{Row: minInt(a.Row, b.Row), Col: minInt(a.Col, b.Col)}
+					p.image = TileLocation{Coord: start, IsLandscape: landscape}
+					p.imageCells = rectCells(start, p.image.tileEnd(size))
+					if (p.imageCells == p.cells) != self {
+						continue
+					}
+					if !self && !p.imageCells.and(used.or(p.cells)).isEmpty() {
+						continue
+					}
+					// The first pair has nothing else to connect to.
+					if g.c.Connected && !self && used.isEmpty() && p.imageCells.and(p.cells.neighbors()).isEmpty() {
+						continue
+					}
+				}
+				res = append(res, p)
+			}
+		}
+	}
+	return res
+}
+
+// finish moves the layout to start at 0,0 and checks the constraints which
+// can only be verified once all tiles are placed.
+func (g *generator) finish(locs []TileLocation) []TileLocation {
+	start := Coord{Row: kMaxRows, Col: kMaxCols}
+	for _, loc := range locs {
+		start.Row = minInt(start.Row, loc.Coord.Row)
+		start.Col = minInt(start.Col, loc.Coord.Col)
+	}
+	for t := range locs {
+		locs[t].Coord.Row -= start.Row
+		locs[t].Coord.Col -= start.Col
+	}
+	l, err := newLayout(locs, g.rules, false)
+	if err != nil {
+		return nil
+	}
+	if g.c.Connected && l.disconnectedTile() >= 0 {
+		return nil
+	}
+	for row := 0; row <= l.end.Row; row++ {
+		if l.holes.and(rowMasks[row]).count() < g.c.MinHolesPerLine {
+			return nil
+		}
+	}
+	for col := 0; col <= l.end.Col; col++ {
+		if l.holes.and(colMasks[col]).count() < g.c.MinHolesPerLine {
+			return nil
+		}
+	}
+	return locs
+}
+
+// rectCells returns all cells of the rectangle with the given corners.
+func rectCells(start, end Coord) bitset {
+	var res bitset
+	for row := start.Row; row <= end.Row; row++ {
+		for col := start.Col; col <= end.Col; col++ {
+			res.set(cellIndex(row, col))
+		}
+	}
+	return res
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,104 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// checkSymmetric verifies that the image of every tile is a tile of the same
+// size.
+func checkSymmetric(t *testing.T, name string, locs []TileLocation, r Rules, s Symmetry) {
+	t.Helper()
+	l, err := newLayout(locs, r, false)
+	if err != nil {
+		t.Fatalf("%s: invalid layout: %v", name, err)
+	}
+	rows, cols := l.end.Row+1, l.end.Col+1
+	for t1, loc := range locs {
+		a := s.Apply(loc.Coord, rows, cols)
+		b := s.Apply(loc.tileEnd(r.TileSizes[t1]), rows, cols)
+		image := rectCells(Coord{Row: minInt(a.Row, b.Row), Col: minInt(a.Col, b.Col)}, Coord{Row: maxInt(a.Row, b.Row), Col: maxInt(a.Col, b.Col)})
+		t2 := int(l.tiles[image.next(0)])
+		if t2 < 0 || l.tileMasks[t2] != image || r.TileSizes[t2] != r.TileSizes[t1] {
+			t.Errorf("%s: the %v image of tile %d at %v is not a tile", name, s, t1, loc)
+		}
+	}
+}
+
+func TestGenerateLayout(t *testing.T) {
+	tests := []struct {
+		name string
+		c    LayoutConstraints
+	}{
+		{"unconstrained", LayoutConstraints{}},
+		{"official", OfficialConstraints()},
+		{"small box", LayoutConstraints{MaxRows: 8, MaxCols: 9, Connected: true}},
+		{"rotational", LayoutConstraints{Connected: true, Symmetry: Rotate180}},
+		{"horizontal mirror", LayoutConstraints{Connected: true, Symmetry: FlipHorizontal}},
+		{"vertical mirror", LayoutConstraints{Connected: true, Symmetry: FlipVertical}},
+		{"holes per line", LayoutConstraints{Connected: true, MinHolesPerLine: 4}},
+	}
+	r := DefaultRules()
+	for _, tc := range tests {
+		for seed := int64(1); seed <= 10; seed++ {
+			locs, err := GenerateLayout(rand.New(rand.NewSource(seed)), r, tc.c)
+			if err != nil {
+				t.Errorf("%s, seed %d: GenerateLayout() = %v", tc.name, seed, err)
+				continue
+			}
+			// All boxes fit the official frame, so connected layouts are strictly valid.
+			l, err := newLayout(locs, r, tc.c.Connected)
+			if err != nil {
+				t.Errorf("%s, seed %d: invalid layout %v: %v", tc.name, seed, locs, err)
+				continue
+			}
+			if l.end.Row >= kFrameSize || l.end.Col >= kFrameSize || tc.c.MaxRows > 0 && (l.end.Row >= tc.c.MaxRows || l.end.Col >= tc.c.MaxCols) {
+				t.Errorf("%s, seed %d: layout ends at %v", tc.name, seed, l.end)
+			}
+			for row := 0; row <= l.end.Row; row++ {
+				if n := l.holes.and(rowMasks[row]).count(); n < tc.c.MinHolesPerLine {
+					t.Errorf("%s, seed %d: row %d has %d holes", tc.name, seed, row, n)
+				}
+			}
+			for col := 0; col <= l.end.Col; col++ {
+				if n := l.holes.and(colMasks[col]).count(); n < tc.c.MinHolesPerLine {
+					t.Errorf("%s, seed %d: column %d has %d holes", tc.name, seed, col, n)
+				}
+			}
+			if tc.c.Symmetry != Identity {
+				checkSymmetric(t, tc.name, locs, r, tc.c.Symmetry)
+			}
+		}
+	}
+}
+
+func TestGenerateLayoutDeterministic(t *testing.T) {
+	c := LayoutConstraints{Connected: true, Symmetry: Rotate180}
+	l1, err := GenerateLayout(rand.New(rand.NewSource(42)), DefaultRules(), c)
+	if err != nil {
+		t.Fatalf("GenerateLayout() = %v", err)
+	}
+	l2, err := GenerateLayout(rand.New(rand.NewSource(42)), DefaultRules(), c)
+	if err != nil {
+		t.Fatalf("GenerateLayout() = %v", err)
+	}
+	if diff := cmp.Diff(l1, l2); diff != "" {
+		t.Errorf("GenerateLayout with the same seed mismatch (-first +second):\n%s", diff)
+	}
+}
+
+func TestGenerateLayoutErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	if _, err := GenerateLayout(rng, DefaultRules(), LayoutConstraints{MaxRows: kMaxRows + 1}); err == nil {
+		t.Errorf("Expected GenerateLayout to error on a too large box")
+	}
+	if _, err := GenerateLayout(rng, DefaultRules(), LayoutConstraints{Symmetry: Symmetry(-1)}); err == nil {
+		t.Errorf("Expected GenerateLayout to error on an unsupported symmetry")
+	}
+	// 64 holes do not fit in a 7x9 box.
+	if _, err := GenerateLayout(rng, DefaultRules(), LayoutConstraints{MaxRows: 7, MaxCols: 9}); err == nil {
+		t.Errorf("Expected GenerateLayout to error on a box smaller than the tiles")
+	}
+}
@@ -0,0 +1,133 @@
+package board
+
+// Marble is the content of a cell of the board.
+type Marble int
+
+const (
+	// NoHole is a cell not covered by any tile.
+	NoHole Marble = kOutOfBounds
+	// EmptyHole is a hole without a marble.
+	EmptyHole   Marble = kEmptySpace
+	RedMarble   Marble = kRedMarble
+	BlackMarble Marble = kBlackMarble
+)
+
+func (m Marble) String() string {
+	switch m {
+	case NoHole:
+		return "no hole"
+	case EmptyHole:
+		return "empty"
+	case RedMarble:
+		return "red"
+	case BlackMarble:
+		return "black"
+	}
+	return "unknown"
+}
+
+// Rows returns the number of rows of the board, from row 0 to the bottom
+// edge of the lowest tile.
+func (b *KulamiBoard) Rows() int {
+	return b.end.Row + 1
+}
+
+// Cols returns the number of columns of the board, from column 0 to the
+// right edge of the rightmost tile.
+func (b *KulamiBoard) Cols() int {
+	return b.end.Col + 1
+}
+
+// inBounds returns whether a coordinate is within Rows and Cols.
+func (b *KulamiBoard) inBounds(c Coord) bool {
+	return c.Row >= 0 && c.Row <= b.end.Row && c.Col >= 0 && c.Col <= b.end.Col
+}
+
+// TileAt returns the index of the tile covering a cell, or -1 if there is
+// none. Tiles are indexed in the order of the rules' TileSizes.
+func (b *KulamiBoard) TileAt(c Coord) int {
+	if !b.inBounds(c) {
+		return kOutOfBounds
+	}
+	return b.tileAt(c.Row, c.Col)
+}
+
+// MarbleAt returns the content of a cell.
+func (b *KulamiBoard) MarbleAt(c Coord) Marble {
+	if !b.inBounds(c) {
+		return NoHole
+	}
+	return Marble(b.marbleAt(c.Row, c.Col))
+}
+
+// NumTiles returns the number of tiles on the board.
+func (b *KulamiBoard) NumTiles() int {
+	return len(b.rules.TileSizes)
+}
+
+// TileSize returns the number of holes of tile t, which must be between 0
+// and NumTiles()-1.
+func (b *KulamiBoard) TileSize(t int) int {
+	return b.rules.TileSizes[t]
+}
+
+// TileCells returns the holes of tile t in row-major order. t must be
+// between 0 and NumTiles()-1.
+func (b *KulamiBoard) TileCells(t int) []Coord {
+	return b.tileMasks[t].appendCoords(make([]Coord, 0, b.rules.TileSizes[t]))
+}
+
+// TileMarbles returns the number of red and black marbles on tile t, which
+// must be between 0 and NumTiles()-1.
+func (b *KulamiBoard) TileMarbles(t int) (red, black int) {
+	mask := b.tileMasks[t]
+	return b.red.and(mask).count(), b.black.and(mask).count()
+}
+
+// TileOwner returns the color of the majority of marbles on tile t, or
+// EmptyHole if neither player has one. t must be between 0 and NumTiles()-1.
+func (b *KulamiBoard) TileOwner(t int) Marble {
+	switch s := b.tileScore[t]; {
+	case s > 0:
+		return RedMarble
+	case s < 0:
+		return BlackMarble
+	}
+	return EmptyHole
+}
+
+// Moves returns all moves made thus far, in order.
+func (b *KulamiBoard) Moves() []Coord {
+	res := make([]Coord, b.numMoves)
+	for i := range res {
+		res[i] = cellCoord(int(b.moves[i]))
+	}
+	return res
+}
+
+// LastMove returns the last move and whether there is one.
+func (b *KulamiBoard) LastMove() (Coord, bool) {
+	if b.numMoves == 0 {
+		return Coord{}, false
+	}
+	return cellCoord(int(b.moves[b.numMoves-1])), true
+}
+
+// RemainingMarbles returns the number of marbles the given player has left
+// to play.
+func (b *KulamiBoard) RemainingMarbles(isRed bool) int {
+	if isRed {
+		return b.rules.MarblesPerPlayer - b.red.count()
+	}
+	return b.rules.MarblesPerPlayer - b.black.count()
+}
+
+// BlockedTiles returns the tiles on which the next move may not be played
+// because of the last moves, most recent first. See Rules.BlockedMoves.
+func (b *KulamiBoard) BlockedTiles() []int {
+	var res []int
+	for k := 1; k <= b.rules.BlockedMoves && k <= b.numMoves; k++ {
+		res = append(res, int(b.tiles[b.moves[b.numMoves-k]]))
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInspect(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	if _, ok := b.LastMove(); ok {
+		t.Errorf("LastMove() on an empty board returned ok")
+	}
+	for i, m := range sampleMoves {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	if b.Rows() != 9 || b.Cols() != 11 {
+		t.Errorf("dimensions = %dx%d, want 9x11", b.Rows(), b.Cols())
+	}
+	if b.NumTiles() != len(kTileSizes) {
+		t.Errorf("NumTiles() = %d, want %d", b.NumTiles(), len(kTileSizes))
+	}
+	tileTests := []struct {
+		c    Coord
+		want int
+	}{
+		{Coord{Row: 4, Col: 0}, 0},
+		{Coord{Row: 5, Col: 1}, 0},
+		{Coord{Row: 0, Col: 4}, 4},
+		{Coord{Row: 2, Col: 1}, 16},
+		{Coord{Row: 0, Col: 0}, -1},
+		{Coord{Row: -1, Col: 4}, -1},
+		{Coord{Row: 4, Col: 11}, -1},
+		{Coord{Row: 20, Col: 20}, -1},
+	}
+	for _, tc := range tileTests {
+		if got := b.TileAt(tc.c); got != tc.want {
+			t.Errorf("TileAt(%v) = %d, want %d", tc.c, got, tc.want)
+		}
+	}
+	marbleTests := []struct {
+		c    Coord
+		want Marble
+	}{
+		{Coord{Row: 4, Col: 5}, RedMarble},
+		{Coord{Row: 4, Col: 0}, BlackMarble},
+		{Coord{Row: 4, Col: 2}, EmptyHole},
+		{Coord{Row: 0, Col: 0}, NoHole},
+		{Coord{Row: -1, Col: -1}, NoHole},
+	}
+	for _, tc := range marbleTests {
+		if got := b.MarbleAt(tc.c); got != tc.want {
+			t.Errorf("MarbleAt(%v) = %v, want %v", tc.c, got, tc.want)
+		}
+	}
+
+	// Tile 7 is the 2x2 tile at 4,6 with marbles x o / O .
+	if got := b.TileSize(7); got != 4 {
+		t.Errorf("TileSize(7) = %d, want 4", got)
+	}
+	wantCells := []Coord{{Row: 4, Col: 6}, {Row: 4, Col: 7}, {Row: 5, Col: 6}, {Row: 5, Col: 7}}
+	if diff := cmp.Diff(wantCells, b.TileCells(7)); diff != "" {
+		t.Errorf("TileCells(7) returned diff (-want +got):\n%s", diff)
+	}
+	if red, black := b.TileMarbles(7); red != 1 || black != 2 {
+		t.Errorf("TileMarbles(7) = %d, %d, want 1, 2", red, black)
+	}
+	if got := b.TileOwner(7); got != BlackMarble {
+		t.Errorf("TileOwner(7) = %v, want %v", got, BlackMarble)
+	}
+	if got := b.TileOwner(4); got != EmptyHole {
+		t.Errorf("TileOwner(4) = %v, want %v", got, EmptyHole)
+	}
+
+	if diff := cmp.Diff(sampleMoves, b.Moves()); diff != "" {
+		t.Errorf("Moves() returned diff (-want +got):\n%s", diff)
+	}
+	if last, ok := b.LastMove(); !ok || last != sampleMoves[len(sampleMoves)-1] {
+		t.Errorf("LastMove() = %v, %v, want %v, true", last, ok, sampleMoves[len(sampleMoves)-1])
+	}
+	// The last moves were on the 6 at 1,6 and the 4 at 4,6.
+	if diff := cmp.Diff([]int{3, 7}, b.BlockedTiles()); diff != "" {
+		t.Errorf("BlockedTiles() returned diff (-want +got):\n%s", diff)
+	}
+	if got := b.RemainingMarbles(true); got != kNumMarbles-7 {
+		t.Errorf("RemainingMarbles(true) = %d, want %d", got, kNumMarbles-7)
+	}
+	if got := b.RemainingMarbles(false); got != kNumMarbles-6 {
+		t.Errorf("RemainingMarbles(false) = %d, want %d", got, kNumMarbles-6)
+	}
+
+	// The returned slices are copies.
+	hash := b.Hash()
+	b.Moves()[0] = Coord{}
+	b.TileCells(7)[0] = Coord{}
+	if b.Hash() != hash || b.Moves()[0] != sampleMoves[0] || b.TileCells(7)[0] != wantCells[0] {
+		t.Errorf("modifying the returned slices changed the board")
+	}
+}
@@ -0,0 +1,116 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonBoard is the JSON form of a board. Only the rules, scoring, layout and
+// moves are read back; the other fields are derived from them.
+type jsonBoard struct {
+	Rules      *Rules         `json:"rules,omitempty"`
+	Scoring    Scoring        `json:"scoring"`
+	Layout     []TileLocation `json:"layout"`
+	Setup      int            `json:"setup,omitempty"`
+	Moves      []jsonMove     `json:"moves"`
+	Tiles      []jsonTile     `json:"tiles,omitempty"`
+	RedScore   int            `json:"redScore"`
+	BlackScore int            `json:"blackScore"`
+	ToMove     string         `json:"toMove,omitempty"`
+	LegalMoves []Coord        `json:"legalMoves"`
+}
+
+type jsonMove struct {
+	Coord
+	Player string `json:"player"`
+}
+
+type jsonTile struct {
+	Size   int     `json:"size"`
+	Red    int     `json:"red"`
+	Black  int     `json:"black"`
+	Owner  string  `json:"owner,omitempty"`
+	Coords []Coord `json:"coords"`
+}
+
+func playerName(isRed bool) string {
+	if isRed {
+		return "red"
+	}
+	return "black"
+}
+
+// MarshalJSON encodes the board as a JSON object with its rules, scoring,
+// tile layout and move history, including the number of set-up moves at its
+// start if any, followed by the state derived from them:
+// marble counts and owner of every tile, both scores, the side to move and
+// the legal moves.
+func (b *KulamiBoard) MarshalJSON() ([]byte, error) {
+	r := b.Rules()
+	res := jsonBoard{
+		Rules:      &r,
+		Scoring:    b.scoring,
+		Layout:     b.Layout(),
+		Setup:      b.setup,
+		Moves:      make([]jsonMove, b.numMoves),
+		Tiles:      make([]jsonTile, len(r.TileSizes)),
+		RedScore:   b.RedScore(),
+		BlackScore: b.BlackScore(),
+		LegalMoves: b.LegalMoves(),
+	}
+	for i := range res.Moves {
+		m := int(b.moves[i])
+		res.Moves[i] = jsonMove{Coord: cellCoord(m), Player: playerName(b.red.has(m))}
+	}
+	for t := range res.Tiles {
+		tile := jsonTile{Size: b.TileSize(t), Coords: b.TileCells(t)}
+		tile.Red, tile.Black = b.TileMarbles(t)
+		if owner := b.TileOwner(t); owner != EmptyHole {
+			tile.Owner = playerName(owner == RedMarble)
+		}
+		res.Tiles[t] = tile
+	}
+	if len(res.LegalMoves) > 0 {
+		res.ToMove = playerName(b.IsRedsTurn())
+	} else {
+		res.LegalMoves = []Coord{}
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON rebuilds a board from the rules, scoring, layout and moves
+// of a JSON object in the format of MarshalJSON, replaying the moves. Set-up
+// moves are only checked to be on empty holes. Missing rules mean
+// DefaultRules. Invalid layouts or illegal moves are rejected.
+func (b *KulamiBoard) UnmarshalJSON(data []byte) error {
+	var in jsonBoard
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	r := DefaultRules()
+	if in.Rules != nil {
+		r = *in.Rules
+	}
+	res, err := NewWithRules(in.Layout, r, WithScoring(in.Scoring))
+	if err != nil {
+		return err
+	}
+	if in.Setup < 0 || in.Setup > len(in.Moves) {
+		return fmt.Errorf("%d set-up moves out of %d moves", in.Setup, len(in.Moves))
+	}
+	for i, m := range in.Moves {
+		if m.Player != "red" && m.Player != "black" {
+			return fmt.Errorf("move %d: unknown player %q", i+1, m.Player)
+		}
+		if i < in.Setup {
+			err = res.placeSetup(m.Coord, m.Player == "red")
+		} else {
+			err = res.Move(m.Coord, m.Player == "red")
+		}
+		if err != nil {
+			return fmt.Errorf("move %d: %v", i+1, err)
+		}
+	}
+	*b = *res
+	return nil
+}
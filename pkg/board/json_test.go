@@ -0,0 +1,124 @@
+package board
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	b, err := New(sampleTiles, WithScoring(GroupScoring))
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for _, m := range sampleMoves {
+		if err := b.Move(m, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var got KulamiBoard
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if got.String() != b.String() || got.Hash() != b.Hash() || got.Scoring() != GroupScoring {
+		t.Errorf("json.Unmarshal returned:\n%s\nExpected:\n%s\n", got.String(), b.String())
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for _, m := range sampleMoves {
+		if err := b.Move(m, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var got struct {
+		Rules   Rules  `json:"rules"`
+		Scoring string `json:"scoring"`
+		Layout  []TileLocation
+		Moves   []struct {
+			Row, Col int
+			Player   string
+		}
+		Tiles []struct {
+			Size, Red, Black int
+			Owner            string
+			Coords           []Coord
+		}
+		RedScore, BlackScore int
+		ToMove               string
+		LegalMoves           []Coord
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if diff := cmp.Diff(DefaultRules(), got.Rules); diff != "" {
+		t.Errorf("rules mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(sampleTiles, got.Layout); diff != "" {
+		t.Errorf("layout mismatch (-want +got):\n%s", diff)
+	}
+	if got.Scoring != "tile" || got.RedScore != 9 || got.BlackScore != 10 || got.ToMove != "black" {
+		t.Errorf("got scoring %q, scores %d:%d, %s to move; want tile, 9:10, black to move", got.Scoring, got.RedScore, got.BlackScore, got.ToMove)
+	}
+	if len(got.Moves) != len(sampleMoves) || got.Moves[0].Player != "red" || got.Moves[1].Player != "black" {
+		t.Errorf("got moves %v", got.Moves)
+	}
+	// Tile 0 at 4,0 holds 4,1 (red) and 4,0 (black).
+	if tile := got.Tiles[0]; tile.Size != 6 || tile.Red != 1 || tile.Black != 1 || tile.Owner != "" || len(tile.Coords) != 6 {
+		t.Errorf("got tile 0 %+v, want size 6 with a red and a black marble and no owner", tile)
+	}
+	// Tile 3 at 1,6 holds 1,6 (red), 2,6 and 2,7 (black).
+	if tile := got.Tiles[3]; tile.Red != 1 || tile.Black != 2 || tile.Owner != "black" {
+		t.Errorf("got tile 3 %+v, want a red and two black marbles owned by black", tile)
+	}
+	if diff := cmp.Diff(b.LegalMoves(), got.LegalMoves); diff != "" {
+		t.Errorf("legal moves mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	if err := b.Move(Coord{Row: 4, Col: 5}, true); err != nil {
+		t.Fatalf("Move(4,5): %v", err)
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	valid := string(data)
+	tests := []struct {
+		name, old, new string
+	}{
+		{"illegal move", `"row":4,"player":"red"`, `"row":4,"player":"black"},{"col":5,"row":4,"player":"red"`},
+		{"unknown player", `"player":"red"`, `"player":"blue"`},
+		{"overlapping tiles", `{"coord":{"col":0,"row":4}}`, `{"coord":{"col":1,"row":4}}`},
+		{"bad rules", `"marblesPerPlayer":28`, `"marblesPerPlayer":0`},
+		{"bad scoring", `"scoring":"tile"`, `"scoring":"area"`},
+	}
+	for _, tc := range tests {
+		if !strings.Contains(valid, tc.old) {
+			t.Fatalf("%s: %s not found in %s", tc.name, tc.old, valid)
+		}
+		var got KulamiBoard
+		if err := json.Unmarshal([]byte(strings.Replace(valid, tc.old, tc.new, 1)), &got); err == nil {
+			t.Errorf("%s: expected json.Unmarshal to error", tc.name)
+		}
+	}
+}
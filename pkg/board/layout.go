@@ -0,0 +1,157 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+)
+
+// kFrameSize is the size of the square frame official layouts must fit in.
+const kFrameSize = 10
+
+// Reasons for a tile layout to be invalid. Errors returned by New and
+// ValidateLayout wrap one of them in a *LayoutError.
+var (
+	ErrTileCount     = errors.New("wrong number of tiles")
+	ErrNegativeCoord = errors.New("negative tile coordinate")
+	ErrTooLarge      = errors.New("layout too large")
+	ErrOverlap       = errors.New("tiles overlap")
+	ErrDisconnected  = errors.New("tiles are not connected")
+	ErrExceedsFrame  = errors.New("layout does not fit the frame")
+)
+
+// LayoutError describes why a tile layout is invalid.
+type LayoutError struct {
+	Err   error // One of the Err* reasons above.
+	Tile  int   // The offending tile, or -1 if the layout as a whole is invalid.
+	Other int   // The tile overlapping Tile, or -1.
+	Coord Coord // The offending cell, if any.
+	msg   string
+}
+
+func (e *LayoutError) Error() string {
+	return e.msg
+}
+
+func (e *LayoutError) Unwrap() error {
+	return e.Err
+}
+
+func layoutError(err error, tile, other int, c Coord, format string, args ...interface{}) *LayoutError {
+	return &LayoutError{Err: err, Tile: tile, Other: other, Coord: c, msg: fmt.Sprintf(format, args...)}
+}
+
+// layout is the immutable part of a board. It is shared between clones.
+type layout struct {
+	locs      []TileLocation    // Upper left corners of the tiles.
+	rules     Rules             // The variant being played.
+	maxMoves  int               // Total number of marbles of both players.
+	end       Coord             // The lower-right corner of the board.
+	holes     bitset            // All cells covered by tiles.
+	tiles     [kMaxCells]int8   // Index of tile by cell.
+	tileMasks [kMaxTiles]bitset // Cells of each tile.
+	scoring   Scoring           // How the score is computed.
+}
+
+// ValidateLayout checks that the tile locations form a valid board under the
+// given rules: there is a location for every tile, all coordinates are
+// non-negative and tiles do not overlap. In strict mode, it also checks the
+// official constraints: the tiles are edge-connected into a single island
+// which fits in a 10x10 frame.
+func ValidateLayout(locs []TileLocation, r Rules, strict bool) error {
+	_, err := newLayout(locs, r, strict)
+	return err
+}
+
+// newLayout validates and builds the tile layout of a board.
+func newLayout(locs []TileLocation, r Rules, strict bool) (*layout, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if len(locs) != len(r.TileSizes) {
+		return nil, layoutError(ErrTileCount, -1, -1, Coord{}, "need exactly %v tile locations, got %v", len(r.TileSizes), len(locs))
+	}
+	l := &layout{
+		locs:     append([]TileLocation(nil), locs...),
+		rules:    r.clone(),
+		maxMoves: 2 * r.MarblesPerPlayer,
+	}
+	// Compute the board range.
+	start := Coord{Row: kMaxRows, Col: kMaxCols}
+	for t, loc := range locs {
+		if loc.Coord.Row < 0 || loc.Coord.Col < 0 {
+			return nil, layoutError(ErrNegativeCoord, t, -1, loc.Coord, "tile %d has negative coordinates %v", t, loc.Coord)
+		}
+		end := loc.tileEnd(r.TileSizes[t])
+		if end.Row >= kMaxRows || end.Col >= kMaxCols {
+			return nil, layoutError(ErrTooLarge, t, -1, end, "tile %d ends at %v, beyond the maximal board size of %dx%d", t, end, kMaxRows, kMaxCols)
+		}
+		if end.Row > l.end.Row {
+			l.end.Row = end.Row
+		}
+		if end.Col > l.end.Col {
+			l.end.Col = end.Col
+		}
+		if loc.Coord.Row < start.Row {
+			start.Row = loc.Coord.Row
+		}
+		if loc.Coord.Col < start.Col {
+			start.Col = loc.Coord.Col
+		}
+	}
+	for i := range l.tiles {
+		l.tiles[i] = kOutOfBounds
+	}
+	for t, loc := range locs {
+		end := loc.tileEnd(r.TileSizes[t])
+		for row := loc.Coord.Row; row <= end.Row; row++ {
+			for col := loc.Coord.Col; col <= end.Col; col++ {
+				i := cellIndex(row, col)
+				if o := l.tiles[i]; o != kOutOfBounds {
+					return nil, layoutError(ErrOverlap, t, int(o), Coord{Row: row, Col: col}, "tiles %d and %d intersect on %v", t, o, Coord{Row: row, Col: col})
+				}
+				l.tiles[i] = int8(t)
+				l.holes.set(i)
+				l.tileMasks[t].set(i)
+			}
+		}
+	}
+	if !strict {
+		return l, nil
+	}
+	if rows, cols := l.end.Row-start.Row+1, l.end.Col-start.Col+1; rows > kFrameSize || cols > kFrameSize {
+		return nil, layoutError(ErrExceedsFrame, -1, -1, Coord{}, "layout of %dx%d does not fit in a %dx%d frame", rows, cols, kFrameSize, kFrameSize)
+	}
+	if t := l.disconnectedTile(); t >= 0 {
+		return nil, layoutError(ErrDisconnected, t, -1, locs[t].Coord, "tile %d is not connected to tile 0", t)
+	}
+	return l, nil
+}
+
+// disconnectedTile returns a tile which cannot be reached from tile 0 through
+// tiles sharing an edge, or -1 if all tiles are connected.
+func (l *layout) disconnectedTile() int {
+	numTiles := len(l.rules.TileSizes)
+	reached := l.tileMasks[0]
+	for grown := true; grown; {
+		grown = false
+		border := reached.neighbors()
+		for t := 1; t < numTiles; t++ {
+			if !l.tileMasks[t].and(reached).isEmpty() || l.tileMasks[t].and(border).isEmpty() {
+				continue
+			}
+			reached = reached.or(l.tileMasks[t])
+			grown = true
+		}
+	}
+	for t := 1; t < numTiles; t++ {
+		if l.tileMasks[t].and(reached).isEmpty() {
+			return t
+		}
+	}
+	return -1
+}
+
+// Layout returns the tile locations the board was created with.
+func (b *KulamiBoard) Layout() []TileLocation {
+	return append([]TileLocation(nil), b.locs...)
+}
@@ -0,0 +1,77 @@
+package board
+
+import (
+	"errors"
+	"testing"
+)
+
+// withTiles returns a copy of the sample layout with some tiles moved.
+func withTiles(moved map[int]TileLocation) []TileLocation {
+	res := append([]TileLocation(nil), sampleTiles...)
+	for t, loc := range moved {
+		res[t] = loc
+	}
+	return res
+}
+
+// strictTiles is the sample layout rearranged to fit in the official frame.
+var strictTiles = withTiles(map[int]TileLocation{
+	11: {Coord: Coord{Row: 0, Col: 6}, IsLandscape: true},
+	14: {Coord: Coord{Row: 8, Col: 2}, IsLandscape: true},
+})
+
+func TestValidateLayout(t *testing.T) {
+	disconnected := withTiles(map[int]TileLocation{
+		11: {Coord: Coord{Row: 0, Col: 6}, IsLandscape: true},
+		14: {Coord: Coord{Row: 8, Col: 0}},
+	})
+	tests := []struct {
+		name      string
+		locs      []TileLocation
+		strict    bool
+		wantErr   error
+		wantTile  int
+		wantOther int
+	}{
+		{name: "sample", locs: sampleTiles},
+		{name: "sample strict", locs: sampleTiles, strict: true, wantErr: ErrExceedsFrame, wantTile: -1, wantOther: -1},
+		{name: "rearranged strict", locs: strictTiles, strict: true},
+		{name: "disconnected", locs: disconnected},
+		{name: "disconnected strict", locs: disconnected, strict: true, wantErr: ErrDisconnected, wantTile: 14, wantOther: -1},
+		{name: "too few tiles", locs: sampleTiles[1:], wantErr: ErrTileCount, wantTile: -1, wantOther: -1},
+		{name: "negative row", locs: withTiles(map[int]TileLocation{3: {Coord: Coord{Row: -1, Col: 6}}}), wantErr: ErrNegativeCoord, wantTile: 3, wantOther: -1},
+		{name: "negative col", locs: withTiles(map[int]TileLocation{13: {Coord: Coord{Row: 0, Col: -2}}}), wantErr: ErrNegativeCoord, wantTile: 13, wantOther: -1},
+		{name: "too large", locs: withTiles(map[int]TileLocation{0: {Coord: Coord{Row: 0, Col: kMaxCols - 1}}}), wantErr: ErrTooLarge, wantTile: 0, wantOther: -1},
+		{name: "overlap", locs: withTiles(map[int]TileLocation{16: {Coord: Coord{Row: 0, Col: 4}}}), wantErr: ErrOverlap, wantTile: 16, wantOther: 4},
+	}
+	for _, tc := range tests {
+		err := ValidateLayout(tc.locs, DefaultRules(), tc.strict)
+		if tc.wantErr == nil {
+			if err != nil {
+				t.Errorf("%s: ValidateLayout() = %v, want nil", tc.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: ValidateLayout() = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		var le *LayoutError
+		if !errors.As(err, &le) {
+			t.Errorf("%s: ValidateLayout() = %v, want a *LayoutError", tc.name, err)
+			continue
+		}
+		if le.Tile != tc.wantTile || le.Other != tc.wantOther {
+			t.Errorf("%s: ValidateLayout() tiles = %d, %d, want %d, %d", tc.name, le.Tile, le.Other, tc.wantTile, tc.wantOther)
+		}
+	}
+}
+
+func TestNewStrictLayout(t *testing.T) {
+	if _, err := New(sampleTiles, StrictLayout()); !errors.Is(err, ErrExceedsFrame) {
+		t.Errorf("New(sampleTiles, StrictLayout()) = %v, want %v", err, ErrExceedsFrame)
+	}
+	if _, err := New(strictTiles, StrictLayout()); err != nil {
+		t.Errorf("New(strictTiles, StrictLayout()) = %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package board
+
+import "fmt"
+
+// Scoring selects how the score of a game is computed.
+type Scoring int
+
+const (
+	// TileScoring awards each tile to the player with the majority of marbles
+	// on it. This is the basic Kulami rule.
+	TileScoring Scoring = iota
+	// GroupScoring additionally awards each player one point per marble in
+	// their largest orthogonally connected group of marbles.
+	GroupScoring
+)
+
+func (s Scoring) String() string {
+	switch s {
+	case TileScoring:
+		return "tile"
+	case GroupScoring:
+		return "group"
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the scoring mode as its name.
+func (s Scoring) MarshalText() ([]byte, error) {
+	if s != TileScoring && s != GroupScoring {
+		return nil, fmt.Errorf("unknown scoring mode %d", s)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a scoring mode from its name.
+func (s *Scoring) UnmarshalText(text []byte) error {
+	for _, sc := range []Scoring{TileScoring, GroupScoring} {
+		if string(text) == sc.String() {
+			*s = sc
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown scoring %q", text)
+}
+
+// Option configures a board created by New.
+type Option func(*options)
+
+type options struct {
+	scoring      Scoring
+	strictLayout bool
+	blackFirst   bool
+}
+
+// WithScoring sets the scoring mode of the board. The default is TileScoring.
+func WithScoring(s Scoring) Option {
+	return func(o *options) {
+		o.scoring = s
+	}
+}
+
+// StrictLayout makes New reject layouts which are not physically legal in
+// the official game; see ValidateLayout.
+func StrictLayout() Option {
+	return func(o *options) {
+		o.strictLayout = true
+	}
+}
+
+// BlackFirst makes ParseDiagram assume that Black made the first move when
+// the diagram has as many black marbles as red ones, so that Black is to
+// move. Other functions ignore it.
+func BlackFirst() Option {
+	return func(o *options) {
+		o.blackFirst = true
+	}
+}
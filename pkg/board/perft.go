@@ -0,0 +1,52 @@
+package board
+
+// Perft counts the positions reached after exactly depth more moves, by
+// playing all legal moves with Move and taking them back with UndoLastMove.
+// Games which end sooner do not count. Comparing the counts with known values
+// verifies move generation as a whole. The board is left unchanged. A
+// negative depth reaches no positions.
+func (b *KulamiBoard) Perft(depth int) uint64 {
+	if depth < 0 {
+		return 0
+	}
+	return b.Clone().perft(depth)
+}
+
+// PerftDivide returns the Perft counts of depth-1 after each legal move, so
+// their sum is Perft(depth). It helps find which move a wrong count is in.
+func (b *KulamiBoard) PerftDivide(depth int) map[Coord]uint64 {
+	res := make(map[Coord]uint64)
+	if depth < 1 {
+		return res
+	}
+	c := b.Clone()
+	isRed := c.IsRedsTurn()
+	for _, m := range c.LegalMoves() {
+		if err := c.Move(m, isRed); err != nil {
+			panic(err) // A bug in move generation.
+		}
+		res[m] = c.perft(depth - 1)
+		c.UndoLastMove()
+	}
+	return res
+}
+
+func (b *KulamiBoard) perft(depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+	moves := b.LegalMoves()
+	if depth == 1 {
+		return uint64(len(moves))
+	}
+	isRed := b.IsRedsTurn()
+	var res uint64
+	for _, m := range moves {
+		if err := b.Move(m, isRed); err != nil {
+			panic(err) // A bug in move generation.
+		}
+		res += b.perft(depth - 1)
+		b.UndoLastMove()
+	}
+	return res
+}
@@ -0,0 +1,131 @@
+package board
+
+import "testing"
+
+// Reference Perft counts, cross-checked against the original array-based
+// implementation of the board. The generated layouts are those of
+// GenerateLayout with OfficialConstraints and seeds 1 to 3.
+var perftTests = []struct {
+	name   string
+	layout string
+	moves  []Coord
+	want   []uint64 // Perft counts from depth 1.
+}{
+	{
+		name:   "sample",
+		layout: "4,0 6,2L 4,3L 1,6 0,4 2,4 2,2 4,6 7,5 1,1L 2,8 5,8L 6,5L 4,2 4,9L 2,0 2,1",
+		want:   []uint64{64, 706, 6688, 59946},
+	},
+	{
+		name:   "sample midgame",
+		layout: "4,0 6,2L 4,3L 1,6 0,4 2,4 2,2 4,6 7,5 1,1L 2,8 5,8L 6,5L 4,2 4,9L 2,0 2,1",
+		moves:  sampleMoves,
+		want:   []uint64{6, 39, 307, 2276},
+	},
+	{
+		name:   "generated 1",
+		layout: "3,6 6,6 5,8 0,7 0,5 7,4 7,2 8,8 5,2 1,1 9,0L 4,1 3,5 8,0L 4,4 6,4L 0,3L",
+		want:   []uint64{64, 642, 5438, 44656},
+	},
+	{
+		name:   "generated 2",
+		layout: "3,1L 5,2L 7,1L 0,0 3,4 1,4 5,5 7,6 5,0 9,0L 2,9 2,6L 3,7 9,7L 0,9 7,4L 9,5L",
+		want:   []uint64{64, 608, 4788, 36642},
+	},
+	{
+		name:   "generated 3",
+		layout: "7,1L 3,3 5,0L 4,5 8,4 6,7 8,8 8,6 3,1 0,4 4,7L 1,7 1,5 0,5L 2,2L 7,0 9,1L",
+		want:   []uint64{64, 630, 5172, 41056},
+	},
+}
+
+func TestPerft(t *testing.T) {
+	for _, tc := range perftTests {
+		locs, err := parseLayout(tc.layout)
+		if err != nil {
+			t.Fatalf("%s: parseLayout() failed: %v", tc.name, err)
+		}
+		b, err := New(locs)
+		if err != nil {
+			t.Fatalf("%s: Error initializing board: %v", tc.name, err)
+		}
+		for i, m := range tc.moves {
+			if err := b.Move(m, i%2 == 0); err != nil {
+				t.Fatalf("%s: Move(%v) failed: %v", tc.name, m, err)
+			}
+		}
+		hash := b.Hash()
+		for i, want := range tc.want {
+			depth := i + 1
+			if testing.Short() && depth > 3 {
+				break
+			}
+			if got := b.Perft(depth); got != want {
+				t.Errorf("%s: Perft(%d) = %d, want %d", tc.name, depth, got, want)
+			}
+		}
+		if b.Hash() != hash || b.NumMoves() != len(tc.moves) {
+			t.Errorf("%s: Perft() changed the board", tc.name)
+		}
+	}
+}
+
+func TestPerftNegativeDepth(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	if got := b.Perft(0); got != 1 {
+		t.Errorf("Perft(0) = %d, want 1", got)
+	}
+	for _, depth := range []int{-1, -5} {
+		if got := b.Perft(depth); got != 0 {
+			t.Errorf("Perft(%d) = %d, want 0", depth, got)
+		}
+		if got := b.PerftDivide(depth); len(got) != 0 {
+			t.Errorf("PerftDivide(%d) = %v, want no moves", depth, got)
+		}
+	}
+}
+
+func TestPerftDivide(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	divide := b.PerftDivide(3)
+	if len(divide) != len(b.LegalMoves()) {
+		t.Errorf("PerftDivide(3) has %d moves, want %d", len(divide), len(b.LegalMoves()))
+	}
+	var sum uint64
+	for m, n := range divide {
+		c := b.Clone()
+		if err := c.Move(m, c.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+		if want := c.Perft(2); n != want {
+			t.Errorf("PerftDivide(3)[%v] = %d, want %d", m, n, want)
+		}
+		sum += n
+	}
+	if want := b.Perft(3); sum != want {
+		t.Errorf("PerftDivide(3) sums up to %d, want %d", sum, want)
+	}
+	if got := b.PerftDivide(0); len(got) != 0 {
+		t.Errorf("PerftDivide(0) = %v, want empty", got)
+	}
+}
+
+func BenchmarkPerft(b *testing.B) {
+	board := benchmarkBoard(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.Perft(4)
+	}
+}
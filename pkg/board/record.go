@@ -0,0 +1,446 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Record is a complete game, as stored in a game record file.
+//
+// The file format is modeled after chess PGN: a section of headers, one per
+// line, followed by the numbered moves of each round and the result:
+//
+//	[Date "2021.04.02"]
+//	[Red "Alice"]
+//	[Black "Bob"]
+//	[Rules "standard"]
+//	[Scoring "tile"]
+//	[Layout "a5 c7L d5L g2 e1 e3 c3 g5 f8 b2L i3 i6L f7L c5 j5L a3 b3"]
+//	[Result "0-1"]
+//
+//	1. f5 a5 2. a3 h3 3. h5 d5 {a comment} 4. b5 g5 0-1
+//
+// Coordinates are in the algebraic notation of Coord.String; the legacy
+// "row,col" form is also accepted. Tiles of the layout are given by their
+// upper left corner, with an L suffix for landscape tiles. Red moves first in
+// every round; a game in which Black moved first starts with "1...". A
+// position set up from a diagram has a Setup header with the number of
+// set-up moves.
+type Record struct {
+	Red, Black     string            // Names of the players.
+	RedAI, BlackAI string            // Types of AI of the players, empty for humans.
+	Date           string            // Date of the game, as YYYY.MM.DD.
+	Result         string            // "1-0", "0-1", "1/2-1/2", or "*" if the game is not over.
+	Tags           map[string]string // All other headers.
+	Rules          Rules
+	Scoring        Scoring
+	Layout         []TileLocation
+	BlackFirst     bool // Whether Black made the first move.
+	Setup          int  // Number of set-up moves at the start of Moves, see KulamiBoard.SetupMoves.
+	Moves          []Coord
+
+	moveLines []int // Line of each move in the parsed file.
+}
+
+// Headers with a dedicated field in Record, in the order they are written.
+var kRecordHeaders = []string{"Date", "Red", "Black", "RedAI", "BlackAI", "Rules", "Scoring", "Layout", "Setup", "Result"}
+
+// kStandardRules is the value of the Rules header for DefaultRules.
+const kStandardRules = "standard"
+
+// RecordError is an error in a game record.
+type RecordError struct {
+	Line int   // Line of the error in the file, or 0 if unknown.
+	Ply  int   // Number of the illegal move, starting at 1, or 0 for syntax errors.
+	Move Coord // The illegal move, if Ply is set.
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	var res strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&res, "line %d: ", e.Line)
+	}
+	if e.Ply > 0 {
+		fmt.Fprintf(&res, "move %d (%v): ", e.Ply, e.Move)
+	}
+	res.WriteString(e.Err.Error())
+	return res.String()
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// NewRecord returns a record of the game played on a board so far. The
+// players, date and result are left for the caller to fill in.
+func NewRecord(b *KulamiBoard) *Record {
+	rec := &Record{
+		Result:  "*",
+		Rules:   b.Rules(),
+		Scoring: b.scoring,
+		Layout:  b.Layout(),
+		Setup:   b.setup,
+		Moves:   b.Moves(),
+	}
+	if b.numMoves > 0 {
+		rec.BlackFirst = !b.red.has(int(b.moves[0]))
+	}
+	return rec
+}
+
+// Board replays the game on a new board. An illegal move results in a
+// *RecordError with its line in the file.
+func (rec *Record) Board() (*KulamiBoard, error) {
+	b, err := NewWithRules(rec.Layout, rec.Rules, WithScoring(rec.Scoring))
+	if err != nil {
+		return nil, &RecordError{Err: err}
+	}
+	if rec.Setup < 0 || rec.Setup > len(rec.Moves) {
+		return nil, &RecordError{Err: fmt.Errorf("%d set-up moves out of %d moves", rec.Setup, len(rec.Moves))}
+	}
+	isRed := !rec.BlackFirst
+	for i, m := range rec.Moves {
+		if i < rec.Setup {
+			err = b.placeSetup(m, isRed)
+		} else {
+			err = b.Move(m, isRed)
+		}
+		if err != nil {
+			line := 0
+			if i < len(rec.moveLines) {
+				line = rec.moveLines[i]
+			}
+			return nil, &RecordError{Line: line, Ply: i + 1, Move: m, Err: err}
+		}
+		isRed = !isRed
+	}
+	return b, nil
+}
+
+// Validate replays the game and reports the first illegal move.
+func (rec *Record) Validate() error {
+	_, err := rec.Board()
+	return err
+}
+
+// FormatRecord writes a game record.
+func FormatRecord(w io.Writer, rec *Record) error {
+	bw := bufio.NewWriter(w)
+	headers := map[string]string{
+		"Date":    rec.Date,
+		"Red":     rec.Red,
+		"Black":   rec.Black,
+		"RedAI":   rec.RedAI,
+		"BlackAI": rec.BlackAI,
+		"Rules":   formatRules(rec.Rules),
+		"Scoring": rec.Scoring.String(),
+		"Layout":  formatLayout(rec.Layout),
+		"Result":  rec.Result,
+	}
+	if rec.Setup > 0 {
+		headers["Setup"] = strconv.Itoa(rec.Setup)
+	}
+	if headers["Result"] == "" {
+		headers["Result"] = "*"
+	}
+	for _, k := range kRecordHeaders {
+		if v := headers[k]; v != "" {
+			fmt.Fprintf(bw, "[%s %s]\n", k, strconv.Quote(v))
+		}
+	}
+	var tags []string
+	for k := range rec.Tags {
+		if _, ok := headers[k]; !ok {
+			tags = append(tags, k)
+		}
+	}
+	sort.Strings(tags)
+	for _, k := range tags {
+		fmt.Fprintf(bw, "[%s %s]\n", k, strconv.Quote(rec.Tags[k]))
+	}
+	fmt.Fprintln(bw)
+	// Write the moves, a few rounds per line.
+	var line strings.Builder
+	isRed := !rec.BlackFirst
+	round := 1
+	for i, m := range rec.Moves {
+		if isRed || i == 0 {
+			if line.Len() > 60 {
+				fmt.Fprintln(bw, line.String())
+				line.Reset()
+			}
+			if line.Len() > 0 {
+				line.WriteString(" ")
+			}
+			if isRed {
+				fmt.Fprintf(&line, "%d. ", round)
+			} else {
+				fmt.Fprintf(&line, "%d... ", round)
+			}
+		} else {
+			line.WriteString(" ")
+		}
+		line.WriteString(m.String())
+		if !isRed {
+			round++
+		}
+		isRed = !isRed
+	}
+	if line.Len() > 0 {
+		line.WriteString(" ")
+	}
+	line.WriteString(headers["Result"])
+	fmt.Fprintln(bw, line.String())
+	return bw.Flush()
+}
+
+// ParseRecord reads a game record. It only checks the syntax of the file,
+// with each header at most once; use Validate to check that the moves are
+// legal.
+func ParseRecord(r io.Reader) (*Record, error) {
+	rec := &Record{Result: "*", Rules: DefaultRules()}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	inHeaders := true
+	inComment := false
+	seen := make(map[string]bool) // Headers read so far.
+	var result string
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if inHeaders && strings.HasPrefix(line, "[") {
+			k, v, err := parseHeader(line)
+			if err == nil && seen[k] {
+				err = fmt.Errorf("duplicate header %q", k)
+			}
+			if err == nil {
+				seen[k] = true
+				err = rec.setHeader(k, v)
+			}
+			if err != nil {
+				return nil, &RecordError{Line: lineNum, Err: err}
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		inHeaders = false
+		for _, tok := range tokenizeMoves(line, &inComment) {
+			if result != "" {
+				return nil, &RecordError{Line: lineNum, Err: fmt.Errorf("unexpected %q after the result", tok)}
+			}
+			switch {
+			case tok == "1-0" || tok == "0-1" || tok == "1/2-1/2" || tok == "*":
+				result = tok
+			case strings.HasSuffix(tok, "..."):
+				if len(rec.Moves) != 0 || tok != "1..." {
+					return nil, &RecordError{Line: lineNum, Err: fmt.Errorf("unexpected %q, only the first move may be Black's", tok)}
+				}
+				rec.BlackFirst = true
+			case strings.HasSuffix(tok, "."):
+				// A move number; the moves are numbered implicitly.
+			default:
+				c, err := ParseCoord(tok)
+				if err != nil {
+					return nil, &RecordError{Line: lineNum, Err: err}
+				}
+				rec.Moves = append(rec.Moves, c)
+				rec.moveLines = append(rec.moveLines, lineNum)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if inComment {
+		return nil, &RecordError{Line: lineNum, Err: fmt.Errorf("unterminated comment")}
+	}
+	if !seen["Layout"] {
+		return nil, &RecordError{Err: fmt.Errorf("missing Layout header")}
+	}
+	if result != "" && !seen["Result"] {
+		rec.Result = result
+	}
+	if result != "" && result != rec.Result {
+		return nil, &RecordError{Line: lineNum, Err: fmt.Errorf("result %s does not match the Result header %s", result, rec.Result)}
+	}
+	return rec, nil
+}
+
+// kMoveNumber matches a move number and whatever follows it, as in "1.4,5".
+var kMoveNumber = regexp.MustCompile(`^([0-9]+)(\.\.\.|\.)(.*)$`)
+
+// tokenizeMoves splits a line of moves into tokens, skipping {comments}.
+func tokenizeMoves(line string, inComment *bool) []string {
+	var text strings.Builder
+	for _, r := range line {
+		switch {
+		case *inComment:
+			*inComment = r != '}'
+		case r == '{':
+			*inComment = true
+			text.WriteRune(' ')
+		default:
+			text.WriteRune(r)
+		}
+	}
+	var res []string
+	for _, tok := range strings.Fields(text.String()) {
+		if m := kMoveNumber.FindStringSubmatch(tok); m != nil {
+			res = append(res, m[1]+m[2])
+			tok = m[3]
+		}
+		if tok != "" {
+			res = append(res, tok)
+		}
+	}
+	return res
+}
+
+// parseHeader parses a [Key "Value"] line.
+func parseHeader(line string) (string, string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("bad header %q, expected [Key \"Value\"]", line)
+	}
+	line = strings.TrimSpace(line[1 : len(line)-1])
+	i := strings.IndexFunc(line, unicode.IsSpace)
+	if i <= 0 {
+		return "", "", fmt.Errorf("bad header %q, expected [Key \"Value\"]", line)
+	}
+	v, err := strconv.Unquote(strings.TrimSpace(line[i:]))
+	if err != nil {
+		return "", "", fmt.Errorf("bad header value %s: %v", line[i:], err)
+	}
+	return line[:i], v, nil
+}
+
+func (rec *Record) setHeader(k, v string) error {
+	var err error
+	switch k {
+	case "Date":
+		rec.Date = v
+	case "Red":
+		rec.Red = v
+	case "Black":
+		rec.Black = v
+	case "RedAI":
+		rec.RedAI = v
+	case "BlackAI":
+		rec.BlackAI = v
+	case "Result":
+		switch v {
+		case "1-0", "0-1", "1/2-1/2", "*":
+			rec.Result = v
+		default:
+			err = fmt.Errorf("bad result %q, expected 1-0, 0-1, 1/2-1/2 or *", v)
+		}
+	case "Rules":
+		rec.Rules, err = parseRules(v)
+	case "Scoring":
+		err = rec.Scoring.UnmarshalText([]byte(v))
+	case "Layout":
+		rec.Layout, err = parseLayout(v)
+	case "Setup":
+		rec.Setup, err = strconv.Atoi(v)
+	default:
+		if rec.Tags == nil {
+			rec.Tags = map[string]string{}
+		}
+		rec.Tags[k] = v
+	}
+	return err
+}
+
+// formatRules returns the value of the Rules header, e.g.
+// "tiles=6,4,4,2 marbles=6 blocked=1 rowcol=false".
+func formatRules(r Rules) string {
+	if r.equal(DefaultRules()) {
+		return kStandardRules
+	}
+	sizes := make([]string, len(r.TileSizes))
+	for i, s := range r.TileSizes {
+		sizes[i] = strconv.Itoa(s)
+	}
+	return fmt.Sprintf("tiles=%s marbles=%d blocked=%d rowcol=%t", strings.Join(sizes, ","), r.MarblesPerPlayer, r.BlockedMoves, r.RowColumn)
+}
+
+func parseRules(s string) (Rules, error) {
+	if s == kStandardRules {
+		return DefaultRules(), nil
+	}
+	var r Rules
+	seen := map[string]bool{}
+	for _, field := range strings.Fields(s) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 || seen[kv[0]] {
+			return Rules{}, fmt.Errorf("bad rules %q", s)
+		}
+		seen[kv[0]] = true
+		var err error
+		switch kv[0] {
+		case "tiles":
+			for _, t := range strings.Split(kv[1], ",") {
+				var size int
+				if size, err = strconv.Atoi(t); err != nil {
+					break
+				}
+				r.TileSizes = append(r.TileSizes, size)
+			}
+		case "marbles":
+			r.MarblesPerPlayer, err = strconv.Atoi(kv[1])
+		case "blocked":
+			r.BlockedMoves, err = strconv.Atoi(kv[1])
+		case "rowcol":
+			r.RowColumn, err = strconv.ParseBool(kv[1])
+		default:
+			err = fmt.Errorf("unknown rule %q", kv[0])
+		}
+		if err != nil {
+			return Rules{}, fmt.Errorf("bad rules %q: %v", s, err)
+		}
+	}
+	if len(seen) != 4 {
+		return Rules{}, fmt.Errorf("bad rules %q, expected tiles, marbles, blocked and rowcol", s)
+	}
+	if err := r.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return r, nil
+}
+
+func formatLayout(locs []TileLocation) string {
+	res := make([]string, len(locs))
+	for i, l := range locs {
+		res[i] = l.Coord.String()
+		if l.IsLandscape {
+			res[i] += "L"
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+func parseLayout(s string) ([]TileLocation, error) {
+	var res []TileLocation
+	for _, tok := range strings.Fields(s) {
+		var l TileLocation
+		if strings.HasSuffix(tok, "L") {
+			l.IsLandscape = true
+			tok = strings.TrimSuffix(tok, "L")
+		}
+		c, err := ParseCoord(tok)
+		if err != nil {
+			return nil, fmt.Errorf("bad layout: %v", err)
+		}
+		l.Coord = c
+		res = append(res, l)
+	}
+	return res, nil
+}
@@ -0,0 +1,154 @@
+package board
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const sampleRecord = `[Date "2021.04.02"]
+[Red "Alice"]
+[Black "Calculating"]
+[BlackAI "calculating"]
+[Rules "standard"]
+[Scoring "tile"]
+[Layout "a5 c7L d5L g2 e1 e3 c3 g5 f8 b2L i3 i6L f7L c5 j5L a3 b3"]
+[Result "*"]
+[Event "Office league"]
+
+1. f5 a5 2. a3 h3 3. h5 d5 4. b5 g5 5. g8 g3 6. g7 g6 7. g2 *
+`
+
+func TestRecordRoundTrip(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for _, m := range sampleMoves {
+		if err := b.Move(m, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+	}
+	rec := NewRecord(b)
+	rec.Date = "2021.04.02"
+	rec.Red = "Alice"
+	rec.Black = "Calculating"
+	rec.BlackAI = "calculating"
+	rec.Tags = map[string]string{"Event": "Office league"}
+	var out strings.Builder
+	if err := FormatRecord(&out, rec); err != nil {
+		t.Fatalf("FormatRecord: %v", err)
+	}
+	if got := out.String(); got != sampleRecord {
+		t.Errorf("FormatRecord() returned:\n%s\nExpected:\n%s\n", got, sampleRecord)
+	}
+	parsed, err := ParseRecord(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("ParseRecord: %v", err)
+	}
+	if diff := cmp.Diff(rec, parsed, cmpopts.IgnoreUnexported(Record{})); diff != "" {
+		t.Errorf("ParseRecord mismatch (-want +got):\n%s", diff)
+	}
+	replayed, err := parsed.Board()
+	if err != nil {
+		t.Fatalf("Board(): %v", err)
+	}
+	if got, want := replayed.String(), b.String(); got != want {
+		t.Errorf("Board() returned:\n%s\nExpected:\n%s\n", got, want)
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	// Records written before algebraic notation use "row,col" coordinates.
+	const input = `[Layout "0,0L 1,0L 0,2"]
+[Rules "tiles=2,2,2 marbles=2 blocked=1 rowcol=false"]
+[Scoring "group"]
+
+{Black opens.} 1... 0,0 2.1,1 {a
+multi-line comment} 0,1 3. 0,2 1-0
+`
+	rec, err := ParseRecord(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRecord: %v", err)
+	}
+	want := &Record{
+		Result: "1-0",
+		Rules: Rules{
+			TileSizes:        []int{2, 2, 2},
+			MarblesPerPlayer: 2,
+			BlockedMoves:     1,
+		},
+		Scoring: GroupScoring,
+		Layout: []TileLocation{
+			{Coord: Coord{Row: 0, Col: 0}, IsLandscape: true},
+			{Coord: Coord{Row: 1, Col: 0}, IsLandscape: true},
+			{Coord: Coord{Row: 0, Col: 2}},
+		},
+		BlackFirst: true,
+		Moves:      []Coord{{Row: 0, Col: 0}, {Row: 1, Col: 1}, {Row: 0, Col: 1}, {Row: 0, Col: 2}},
+	}
+	if diff := cmp.Diff(want, rec, cmpopts.IgnoreUnexported(Record{})); diff != "" {
+		t.Errorf("ParseRecord mismatch (-want +got):\n%s", diff)
+	}
+	b, err := rec.Board()
+	if err != nil {
+		t.Fatalf("Board(): %v", err)
+	}
+	// Red has tiles 1 and 2, and a group of 1. Black has tile 0, and a group of 2.
+	if got, want := b.ScoreDiff(true), 1; got != want {
+		t.Errorf("ScoreDiff(true) = %d, want %d", got, want)
+	}
+}
+
+func TestParseRecordErrors(t *testing.T) {
+	const layout = `[Layout "a5 c7L d5L g2 e1 e3 c3 g5 f8 b2L i3 i6L f7L c5 j5L a3 b3"]` + "\n"
+	tests := []struct {
+		name     string
+		input    string
+		wantLine int
+	}{
+		{"bad header", layout + `[Red Alice]`, 2},
+		{"bad rules", layout + `[Rules "tiles=6"]`, 2},
+		{"bad scoring", layout + `[Scoring "area"]`, 2},
+		{"bad result", layout + `[Result "2-0"]`, 2},
+		{"bad layout", `[Layout "a5 c"]`, 1},
+		{"duplicate layout", layout + layout, 2},
+		{"duplicate tag", layout + "[Event \"a\"]\n[Event \"b\"]", 3},
+		{"missing layout", "1. f5 *", 0},
+		{"bad move", layout + "\n1. f5 a5;", 3},
+		{"late black first", layout + "\n1. f5 2... a5", 3},
+		{"moves after result", layout + "\n1. f5 * a5", 3},
+		{"result mismatch", layout + "[Result \"1-0\"]\n1. f5 0-1", 3},
+		{"unterminated comment", layout + "\n1. f5 {a5", 3},
+	}
+	for _, tc := range tests {
+		_, err := ParseRecord(strings.NewReader(tc.input))
+		var re *RecordError
+		if !errors.As(err, &re) {
+			t.Errorf("%s: ParseRecord() = %v, want a *RecordError", tc.name, err)
+			continue
+		}
+		if re.Line != tc.wantLine {
+			t.Errorf("%s: ParseRecord() = %v, want an error on line %d", tc.name, err, tc.wantLine)
+		}
+	}
+}
+
+func TestRecordValidate(t *testing.T) {
+	input := strings.Replace(sampleRecord, "5. g8 g3", "5. g8 f3", 1)
+	rec, err := ParseRecord(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRecord: %v", err)
+	}
+	err = rec.Validate()
+	var re *RecordError
+	if !errors.As(err, &re) {
+		t.Fatalf("Validate() = %v, want a *RecordError", err)
+	}
+	if re.Line != 11 || re.Ply != 10 || re.Move != (Coord{Row: 2, Col: 5}) {
+		t.Errorf("Validate() = %v, want an error for move 10 (f3) on line 11", err)
+	}
+}
@@ -0,0 +1,146 @@
+package board
+
+import "fmt"
+
+// Outcome is who won a game.
+type Outcome int
+
+const (
+	// Undecided means the game is not over.
+	Undecided Outcome = iota
+	// RedWins means Red has the higher score, or Black lost by resignation
+	// or on time.
+	RedWins
+	// BlackWins means Black has the higher score, or Red lost by resignation
+	// or on time.
+	BlackWins
+	// Draw means both players have the same score.
+	Draw
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Undecided:
+		return "undecided"
+	case RedWins:
+		return "red wins"
+	case BlackWins:
+		return "black wins"
+	case Draw:
+		return "draw"
+	}
+	return "unknown"
+}
+
+// EndReason is why a game ended.
+type EndReason int
+
+const (
+	// NotOver means the game is still in progress.
+	NotOver EndReason = iota
+	// OutOfMarbles means all marbles were played.
+	OutOfMarbles
+	// NoLegalMoves means the player to move had marbles left, but nowhere to
+	// put them.
+	NoLegalMoves
+	// Resignation means the loser resigned.
+	Resignation
+	// TimeForfeit means the loser ran out of time.
+	TimeForfeit
+)
+
+func (r EndReason) String() string {
+	switch r {
+	case NotOver:
+		return "not over"
+	case OutOfMarbles:
+		return "out of marbles"
+	case NoLegalMoves:
+		return "no legal moves"
+	case Resignation:
+		return "resignation"
+	case TimeForfeit:
+		return "time forfeit"
+	}
+	return "unknown"
+}
+
+// Result is the outcome of a game.
+type Result struct {
+	Outcome    Outcome
+	Reason     EndReason
+	RedScore   int // Final score of Red, or the current one if the game is not over.
+	BlackScore int // Final score of Black, or the current one if the game is not over.
+}
+
+// Notation returns the result in game record notation: "1-0", "0-1",
+// "1/2-1/2", or "*" if the game is not over.
+func (r Result) Notation() string {
+	switch r.Outcome {
+	case RedWins:
+		return "1-0"
+	case BlackWins:
+		return "0-1"
+	case Draw:
+		return "1/2-1/2"
+	}
+	return "*"
+}
+
+// String describes the result, e.g. "Red wins with final score 12 vs. 10".
+func (r Result) String() string {
+	winner, winScore, loseScore := "Red", r.RedScore, r.BlackScore
+	if r.Outcome == BlackWins {
+		winner, winScore, loseScore = "Black", r.BlackScore, r.RedScore
+	}
+	switch {
+	case r.Outcome == Undecided:
+		return "The game is not over"
+	case r.Reason == Resignation:
+		return fmt.Sprintf("%s wins by resignation", winner)
+	case r.Reason == TimeForfeit:
+		return fmt.Sprintf("%s wins on time", winner)
+	case r.Outcome == Draw:
+		return fmt.Sprintf("The game is a draw with final score %d vs. %d", r.RedScore, r.BlackScore)
+	}
+	return fmt.Sprintf("%s wins with final score %d vs. %d", winner, winScore, loseScore)
+}
+
+// IsGameOver returns whether the player to move has no legal moves left,
+// either because they are out of marbles or because every hole they could
+// play is blocked or taken.
+func (b *KulamiBoard) IsGameOver() bool {
+	return b.legalMask().isEmpty()
+}
+
+// Result returns the result of the game if it is over, decided by the score.
+// Otherwise its outcome is Undecided, with the current scores.
+func (b *KulamiBoard) Result() Result {
+	res := Result{RedScore: b.RedScore(), BlackScore: b.BlackScore()}
+	if !b.IsGameOver() {
+		return res
+	}
+	res.Reason = NoLegalMoves
+	if b.numMoves == b.maxMoves {
+		res.Reason = OutOfMarbles
+	}
+	switch {
+	case res.RedScore > res.BlackScore:
+		res.Outcome = RedWins
+	case res.RedScore < res.BlackScore:
+		res.Outcome = BlackWins
+	default:
+		res.Outcome = Draw
+	}
+	return res
+}
+
+// Forfeit returns the result of the game if a player loses it by Resignation
+// or TimeForfeit, regardless of the score.
+func (b *KulamiBoard) Forfeit(isRed bool, reason EndReason) Result {
+	res := Result{Outcome: RedWins, Reason: reason, RedScore: b.RedScore(), BlackScore: b.BlackScore()}
+	if isRed {
+		res.Outcome = BlackWins
+	}
+	return res
+}
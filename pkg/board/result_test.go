@@ -0,0 +1,150 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResult(t *testing.T) {
+	locs := []TileLocation{
+		{Coord: Coord{Row: 0, Col: 0}},
+		{Coord: Coord{Row: 0, Col: 1}},
+	}
+	tests := []struct {
+		name  string
+		rules Rules
+		moves []Coord
+		want  Result
+	}{
+		{
+			name:  "not over",
+			rules: Rules{TileSizes: []int{2, 2}, MarblesPerPlayer: 2, BlockedMoves: 1},
+			moves: []Coord{{Row: 0, Col: 0}},
+			want:  Result{Outcome: Undecided, Reason: NotOver, RedScore: 2},
+		},
+		{
+			name:  "out of marbles",
+			rules: Rules{TileSizes: []int{2, 2}, MarblesPerPlayer: 2, BlockedMoves: 1},
+			moves: []Coord{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 1, Col: 1}},
+			want:  Result{Outcome: Draw, Reason: OutOfMarbles, RedScore: 2, BlackScore: 2},
+		},
+		{
+			name:  "no legal moves",
+			rules: Rules{TileSizes: []int{2, 1}, MarblesPerPlayer: 2, BlockedMoves: 1},
+			moves: []Coord{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 0}},
+			want:  Result{Outcome: RedWins, Reason: NoLegalMoves, RedScore: 2, BlackScore: 1},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewWithRules(locs, tc.rules)
+			if err != nil {
+				t.Fatalf("Error initializing board: %v", err)
+			}
+			for i, m := range tc.moves {
+				if err := b.Move(m, i%2 == 0); err != nil {
+					t.Fatalf("Move(%v) failed: %v", m, err)
+				}
+			}
+			if got, want := b.IsGameOver(), tc.want.Outcome != Undecided; got != want {
+				t.Errorf("IsGameOver() = %v, want %v", got, want)
+			}
+			if diff := cmp.Diff(tc.want, b.Result()); diff != "" {
+				t.Errorf("Result() returned diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResultSampleGame(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for !b.IsGameOver() {
+		if err := b.Move(b.LegalMoves()[0], b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move() failed: %v", err)
+		}
+	}
+	res := b.Result()
+	if res.RedScore != b.RedScore() || res.BlackScore != b.BlackScore() {
+		t.Errorf("Result() scores = %d vs. %d, want %d vs. %d", res.RedScore, res.BlackScore, b.RedScore(), b.BlackScore())
+	}
+	wantOutcome := Draw
+	if d := b.ScoreDiff(true); d > 0 {
+		wantOutcome = RedWins
+	} else if d < 0 {
+		wantOutcome = BlackWins
+	}
+	if res.Outcome != wantOutcome {
+		t.Errorf("Result().Outcome = %v, want %v", res.Outcome, wantOutcome)
+	}
+	wantReason := NoLegalMoves
+	if b.NumMoves() == 2*kNumMarbles {
+		wantReason = OutOfMarbles
+	}
+	if res.Reason != wantReason {
+		t.Errorf("Result().Reason = %v, want %v", res.Reason, wantReason)
+	}
+}
+
+func TestResultString(t *testing.T) {
+	tests := []struct {
+		res          Result
+		wantNotation string
+		wantString   string
+	}{
+		{
+			res:          Result{RedScore: 3, BlackScore: 5},
+			wantNotation: "*",
+			wantString:   "The game is not over",
+		},
+		{
+			res:          Result{Outcome: BlackWins, Reason: OutOfMarbles, RedScore: 30, BlackScore: 42},
+			wantNotation: "0-1",
+			wantString:   "Black wins with final score 42 vs. 30",
+		},
+		{
+			res:          Result{Outcome: Draw, Reason: NoLegalMoves, RedScore: 36, BlackScore: 36},
+			wantNotation: "1/2-1/2",
+			wantString:   "The game is a draw with final score 36 vs. 36",
+		},
+		{
+			res:          Result{Outcome: RedWins, Reason: Resignation, RedScore: 3, BlackScore: 5},
+			wantNotation: "1-0",
+			wantString:   "Red wins by resignation",
+		},
+		{
+			res:          Result{Outcome: BlackWins, Reason: TimeForfeit},
+			wantNotation: "0-1",
+			wantString:   "Black wins on time",
+		},
+	}
+	for _, tc := range tests {
+		if got := tc.res.Notation(); got != tc.wantNotation {
+			t.Errorf("%+v.Notation() = %q, want %q", tc.res, got, tc.wantNotation)
+		}
+		if got := tc.res.String(); got != tc.wantString {
+			t.Errorf("%+v.String() = %q, want %q", tc.res, got, tc.wantString)
+		}
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	if err := b.Move(Coord{Row: 4, Col: 5}, true); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+	want := Result{Outcome: BlackWins, Reason: Resignation, RedScore: 6}
+	if diff := cmp.Diff(want, b.Forfeit(true, Resignation)); diff != "" {
+		t.Errorf("Forfeit() returned diff (-want +got):\n%s", diff)
+	}
+	want = Result{Outcome: RedWins, Reason: TimeForfeit, RedScore: 6}
+	if diff := cmp.Diff(want, b.Forfeit(false, TimeForfeit)); diff != "" {
+		t.Errorf("Forfeit() returned diff (-want +got):\n%s", diff)
+	}
+}
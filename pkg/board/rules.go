@@ -0,0 +1,83 @@
+package board
+
+import "fmt"
+
+const (
+	// Limits of the fixed-size board representation.
+	kMaxTiles   = 32
+	kMaxBlocked = 4
+)
+
+// Rules describes a variant of the game.
+type Rules struct {
+	// TileSizes is the number of holes of each tile, in the order of the tile
+	// locations. Tiles of 1, 2 and 3 holes are a single row or column, tiles
+	// of 4 holes are 2x2 and tiles of 6 holes are 2x3.
+	TileSizes []int `json:"tileSizes"`
+	// MarblesPerPlayer is the number of marbles each player has. The game is
+	// over when both players run out.
+	MarblesPerPlayer int `json:"marblesPerPlayer"`
+	// BlockedMoves is the number of most recent moves whose tiles may not be
+	// played next.
+	BlockedMoves int `json:"blockedMoves"`
+	// RowColumn is whether every move must be in the row or column of the
+	// last move.
+	RowColumn bool `json:"rowColumn"`
+}
+
+// DefaultRules returns the rules of the retail game: 17 tiles, 28 marbles per
+// player, the tiles of the last two moves are blocked and every move is in
+// the row or column of the last move.
+func DefaultRules() Rules {
+	return Rules{
+		TileSizes:        append([]int(nil), kTileSizes...),
+		MarblesPerPlayer: kNumMarbles,
+		BlockedMoves:     2,
+		RowColumn:        true,
+	}
+}
+
+// Validate returns an error if the rules are not supported.
+func (r Rules) Validate() error {
+	if len(r.TileSizes) == 0 || len(r.TileSizes) > kMaxTiles {
+		return fmt.Errorf("need between 1 and %d tiles, got %d", kMaxTiles, len(r.TileSizes))
+	}
+	for t, size := range r.TileSizes {
+		switch size {
+		case 1, 2, 3, 4, 6:
+		default:
+			return fmt.Errorf("tile %d has unsupported size %d", t, size)
+		}
+	}
+	if r.MarblesPerPlayer <= 0 {
+		return fmt.Errorf("need a positive number of marbles per player, got %d", r.MarblesPerPlayer)
+	}
+	if r.BlockedMoves < 0 || r.BlockedMoves > kMaxBlocked {
+		return fmt.Errorf("need between 0 and %d blocked moves, got %d", kMaxBlocked, r.BlockedMoves)
+	}
+	return nil
+}
+
+// clone deep copies the rules.
+func (r Rules) clone() Rules {
+	r.TileSizes = append([]int(nil), r.TileSizes...)
+	return r
+}
+
+// equal returns whether two rules are the same.
+func (r Rules) equal(o Rules) bool {
+	if len(r.TileSizes) != len(o.TileSizes) {
+		return false
+	}
+	for i := range r.TileSizes {
+		if r.TileSizes[i] != o.TileSizes[i] {
+			return false
+		}
+	}
+	return r.MarblesPerPlayer == o.MarblesPerPlayer && r.BlockedMoves == o.BlockedMoves && r.RowColumn == o.RowColumn
+}
+
+// Rules returns the rules the board was created with.
+func (b *KulamiBoard) Rules() Rules {
+	return b.rules.clone()
+}
@@ -0,0 +1,114 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCustomRules(t *testing.T) {
+	r := Rules{
+		TileSizes:        []int{2, 2, 2},
+		MarblesPerPlayer: 2,
+		BlockedMoves:     1,
+		RowColumn:        false,
+	}
+	// A 2x3 board:
+	// | 0 0 | 2 |
+	// | 1 1 | 2 |
+	locs := []TileLocation{
+		{Coord: Coord{Row: 0, Col: 0}, IsLandscape: true},
+		{Coord: Coord{Row: 1, Col: 0}, IsLandscape: true},
+		{Coord: Coord{Row: 0, Col: 2}},
+	}
+	b, err := NewWithRules(locs, r)
+	if err != nil {
+		t.Fatalf("NewWithRules: %v", err)
+	}
+	steps := []struct {
+		move      Coord
+		wantMoves []Coord
+	}{
+		{
+			move:      Coord{Row: 0, Col: 0},
+			wantMoves: []Coord{{Row: 0, Col: 2}, {Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: 2}},
+		},
+		{
+			move:      Coord{Row: 1, Col: 1},
+			wantMoves: []Coord{{Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 1, Col: 2}},
+		},
+		{
+			// Only the last move blocks its tile.
+			move:      Coord{Row: 0, Col: 1},
+			wantMoves: []Coord{{Row: 0, Col: 2}, {Row: 1, Col: 0}, {Row: 1, Col: 2}},
+		},
+		{
+			// Both players are out of marbles.
+			move: Coord{Row: 0, Col: 2},
+		},
+	}
+	for _, s := range steps {
+		if err := b.Move(s.move, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", s.move.Row, s.move.Col, err)
+		}
+		if diff := cmp.Diff(s.wantMoves, b.LegalMoves()); diff != "" {
+			t.Errorf("after Move(%d,%d): LegalMoves mismatch (-want +got):\n%s", s.move.Row, s.move.Col, diff)
+		}
+	}
+	if got, want := b.RedScore(), 2; got != want {
+		t.Errorf("RedScore() = %d, want %d", got, want)
+	}
+	if got, want := b.BlackScore(), 4; got != want {
+		t.Errorf("BlackScore() = %d, want %d", got, want)
+	}
+	if b.Move(Coord{Row: 1, Col: 0}, true) == nil {
+		t.Errorf("Expected Move(1,0) to error, out of marbles")
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	b, err := NewWithRules(sampleTiles, DefaultRules())
+	if err != nil {
+		t.Fatalf("NewWithRules: %v", err)
+	}
+	for _, m := range sampleMoves {
+		if err := b.Move(m, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%d,%d): %v", m.Row, m.Col, err)
+		}
+	}
+	if got := b.String(); "\n"+got != printOut {
+		t.Errorf("String() returned:\n%s\nExpected:\n%s\n", got, printOut)
+	}
+	// Modifying the returned rules does not affect the board.
+	b.Rules().TileSizes[0] = 2
+	if got, want := b.Rules().TileSizes[0], 6; got != want {
+		t.Errorf("Rules().TileSizes[0] = %d, want %d", got, want)
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *Rules)
+	}{
+		{"no tiles", func(r *Rules) { r.TileSizes = nil }},
+		{"too many tiles", func(r *Rules) { r.TileSizes = make([]int, kMaxTiles+1) }},
+		{"bad tile size", func(r *Rules) { r.TileSizes[3] = 5 }},
+		{"no marbles", func(r *Rules) { r.MarblesPerPlayer = 0 }},
+		{"negative blocked moves", func(r *Rules) { r.BlockedMoves = -1 }},
+		{"too many blocked moves", func(r *Rules) { r.BlockedMoves = kMaxBlocked + 1 }},
+	}
+	if err := DefaultRules().Validate(); err != nil {
+		t.Errorf("DefaultRules().Validate() = %v", err)
+	}
+	for _, tc := range tests {
+		r := DefaultRules()
+		tc.modify(&r)
+		if r.Validate() == nil {
+			t.Errorf("%s: expected Validate() to error", tc.name)
+		}
+		if _, err := NewWithRules(sampleTiles, r); err == nil {
+			t.Errorf("%s: expected NewWithRules to error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package board
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Symmetry is a transformation of a rectangular board onto itself. The
+// rotations and the transpositions also swap the rows and columns of the
+// board, so they only map a board onto itself if it is square.
+type Symmetry int
+
+const (
+	// Identity leaves the board as is.
+	Identity Symmetry = iota
+	// Rotate180 rotates the board by 180 degrees.
+	Rotate180
+	// FlipHorizontal mirrors the board left to right.
+	FlipHorizontal
+	// FlipVertical mirrors the board top to bottom.
+	FlipVertical
+	// Rotate90 rotates the board by 90 degrees clockwise.
+	Rotate90
+	// Rotate270 rotates the board by 90 degrees counterclockwise.
+	Rotate270
+	// Transpose mirrors the board along the diagonal from the upper left
+	// corner.
+	Transpose
+	// AntiTranspose mirrors the board along the diagonal from the upper right
+	// corner.
+	AntiTranspose
+)
+
+// kSymmetries lists all symmetries of a square.
+var kSymmetries = []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipHorizontal, FlipVertical, Transpose, AntiTranspose}
+
+// Symmetries returns the 8 symmetries of a square, starting with Identity.
+func Symmetries() []Symmetry {
+	return append([]Symmetry(nil), kSymmetries...)
+}
+
+func (s Symmetry) String() string {
+	switch s {
+	case Identity:
+		return "identity"
+	case Rotate180:
+		return "rotate180"
+	case FlipHorizontal:
+		return "flip-horizontal"
+	case FlipVertical:
+		return "flip-vertical"
+	case Rotate90:
+		return "rotate90"
+	case Rotate270:
+		return "rotate270"
+	case Transpose:
+		return "transpose"
+	case AntiTranspose:
+		return "anti-transpose"
+	}
+	return "unknown"
+}
+
+// SwapsAxes returns whether the symmetry turns rows into columns.
+func (s Symmetry) SwapsAxes() bool {
+	return s == Rotate90 || s == Rotate270 || s == Transpose || s == AntiTranspose
+}
+
+// Inverse returns the symmetry which undoes s.
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	}
+	return s
+}
+
+// Apply maps a cell of a board of the given dimensions to the transformed
+// board, which has the dimensions swapped if s.SwapsAxes().
+func (s Symmetry) Apply(c Coord, rows, cols int) Coord {
+	switch s {
+	case Rotate180:
+		return Coord{Row: rows - 1 - c.Row, Col: cols - 1 - c.Col}
+	case FlipHorizontal:
+		return Coord{Row: c.Row, Col: cols - 1 - c.Col}
+	case FlipVertical:
+		return Coord{Row: rows - 1 - c.Row, Col: c.Col}
+	case Rotate90:
+		return Coord{Row: c.Col, Col: rows - 1 - c.Row}
+	case Rotate270:
+		return Coord{Row: cols - 1 - c.Col, Col: c.Row}
+	case Transpose:
+		return Coord{Row: c.Col, Col: c.Row}
+	case AntiTranspose:
+		return Coord{Row: cols - 1 - c.Col, Col: rows - 1 - c.Row}
+	}
+	return c
+}
+
+// transformLayout maps every tile of a layout on a board of the given
+// dimensions. Tile t of the result is the image of tile t.
+func transformLayout(locs []TileLocation, sizes []int, s Symmetry, rows, cols int) []TileLocation {
+	res := make([]TileLocation, len(locs))
+	for t, loc := range locs {
+		a := s.Apply(loc.Coord, rows, cols)
+		b := s.Apply(loc.tileEnd(sizes[t]), rows, cols)
+		start := Coord{Row: minInt(a.Row, b.Row), Col: minInt(a.Col, b.Col)}
+		end := Coord{Row: maxInt(a.Row, b.Row), Col: maxInt(a.Col, b.Col)}
+		res[t] = TileLocation{Coord: start, IsLandscape: end.Col-start.Col > end.Row-start.Row}
+	}
+	return res
+}
+
+// TransformLayout maps a valid layout by a symmetry of the board from 0,0 to
+// the lower right corner of its tiles. Tile t of the result is the image of
+// tile t.
+func TransformLayout(locs []TileLocation, r Rules, s Symmetry) ([]TileLocation, error) {
+	l, err := newLayout(locs, r, false)
+	if err != nil {
+		return nil, err
+	}
+	return transformLayout(locs, r.TileSizes, s, l.end.Row+1, l.end.Col+1), nil
+}
+
+// LayoutSymmetries returns the symmetries which map a valid layout onto
+// itself, up to exchanging tiles of the same size. The result always starts
+// with Identity.
+func LayoutSymmetries(locs []TileLocation, r Rules) ([]Symmetry, error) {
+	l, err := newLayout(locs, r, false)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols := l.end.Row+1, l.end.Col+1
+	want := layoutKey(locs, r.TileSizes)
+	var res []Symmetry
+	for _, s := range kSymmetries {
+		if s.SwapsAxes() && rows != cols {
+			continue
+		}
+		if layoutKey(transformLayout(locs, r.TileSizes, s, rows, cols), r.TileSizes) == want {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+// layoutKey returns a string identifying a layout up to exchanging tiles of
+// the same size.
+func layoutKey(locs []TileLocation, sizes []int) string {
+	tiles := make([]string, len(locs))
+	for t, loc := range locs {
+		tiles[t] = fmt.Sprintf("%d@%d,%d,%t", sizes[t], loc.Coord.Row, loc.Coord.Col, loc.IsLandscape)
+	}
+	sort.Strings(tiles)
+	return strings.Join(tiles, " ")
+}
+
+// Transform returns the position mapped by a symmetry: the layout is
+// transformed as by TransformLayout, and so is every move. The result has
+// the same rules, scoring, scores and legal moves, up to the symmetry.
+func (b *KulamiBoard) Transform(s Symmetry) *KulamiBoard {
+	return b.transform(s, transformLayout(b.locs, b.rules.TileSizes, s, b.Rows(), b.Cols()))
+}
+
+// transform replays the moves of the board, mapped by a symmetry, on a new
+// board with the given layout, which must be the image of the board's.
+func (b *KulamiBoard) transform(s Symmetry, locs []TileLocation) *KulamiBoard {
+	l, err := newLayout(locs, b.rules, false)
+	if err != nil {
+		// The image of a valid layout is valid.
+		panic(fmt.Sprintf("transformed layout is invalid: %v", err))
+	}
+	l.scoring = b.scoring
+	res := &KulamiBoard{layout: l}
+	for i := 0; i < b.numMoves; i++ {
+		m := int(b.moves[i])
+		c := s.Apply(cellCoord(m), b.Rows(), b.Cols())
+		res.place(cellIndex(c.Row, c.Col), b.red.has(m))
+	}
+	res.setup = b.setup
+	return res
+}
+
+// Canonical returns the canonical form of the position, and the symmetry
+// which maps the board to it. All positions which are images of each other
+// under symmetries have the same canonical form, with equal String and Hash.
+//
+// Of the images of the position, the canonical form is the one with the
+// smallest layout, then marbles, then most recent moves. Its tiles of each
+// size are ordered by their location in row-major order, so that they get
+// the same indices in all images.
+func (b *KulamiBoard) Canonical() (*KulamiBoard, Symmetry) {
+	var best *KulamiBoard
+	var bestKey string
+	bestSym := Identity
+	for _, s := range kSymmetries {
+		locs := canonicalLayout(transformLayout(b.locs, b.rules.TileSizes, s, b.Rows(), b.Cols()), b.rules.TileSizes)
+		res := b.transform(s, locs)
+		if key := res.positionKey(); best == nil || key < bestKey {
+			best, bestKey, bestSym = res, key, s
+		}
+	}
+	return best, bestSym
+}
+
+// canonicalLayout reorders the locations of the tiles of each size in
+// row-major order.
+func canonicalLayout(locs []TileLocation, sizes []int) []TileLocation {
+	res := append([]TileLocation(nil), locs...)
+	bySize := make(map[int][]int)
+	for t, size := range sizes {
+		bySize[size] = append(bySize[size], t)
+	}
+	for _, tiles := range bySize {
+		sorted := make([]TileLocation, len(tiles))
+		for i, t := range tiles {
+			sorted[i] = locs[t]
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			a, b := sorted[i].Coord, sorted[j].Coord
+			return a.Row < b.Row || a.Row == b.Row && a.Col < b.Col
+		})
+		for i, t := range tiles {
+			res[t] = sorted[i]
+		}
+	}
+	return res
+}
+
+// kMarbleKeys are the characters of the cell contents in positionKey.
+var kMarbleKeys = map[int]byte{kOutOfBounds: ' ', kEmptySpace: '.', kRedMarble: 'x', kBlackMarble: 'o'}
+
+// positionKey returns a string identifying the position: the layout, the
+// marbles, and the moves which constrain the next one.
+func (b *KulamiBoard) positionKey() string {
+	var res strings.Builder
+	fmt.Fprintf(&res, "%dx%d", b.Rows(), b.Cols())
+	for _, loc := range b.locs {
+		fmt.Fprintf(&res, " %d,%d,%t", loc.Coord.Row, loc.Coord.Col, loc.IsLandscape)
+	}
+	res.WriteString(" ")
+	for row := 0; row <= b.end.Row; row++ {
+		for col := 0; col <= b.end.Col; col++ {
+			res.WriteByte(kMarbleKeys[b.marbleAt(row, col)])
+		}
+	}
+	recent := b.rules.BlockedMoves
+	if b.rules.RowColumn && recent == 0 {
+		recent = 1
+	}
+	for k := 1; k <= recent && k <= b.numMoves; k++ {
+		fmt.Fprintf(&res, " %v", cellCoord(int(b.moves[b.numMoves-k])))
+	}
+	fmt.Fprintf(&res, " %t", b.IsRedsTurn())
+	return res.String()
+}
@@ -0,0 +1,199 @@
+package board
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSymmetryApply(t *testing.T) {
+	const rows, cols = 3, 5
+	c := Coord{Row: 0, Col: 1}
+	tests := []struct {
+		s    Symmetry
+		want Coord
+	}{
+		{Identity, Coord{Row: 0, Col: 1}},
+		{Rotate90, Coord{Row: 1, Col: 2}},
+		{Rotate180, Coord{Row: 2, Col: 3}},
+		{Rotate270, Coord{Row: 3, Col: 0}},
+		{FlipHorizontal, Coord{Row: 0, Col: 3}},
+		{FlipVertical, Coord{Row: 2, Col: 1}},
+		{Transpose, Coord{Row: 1, Col: 0}},
+		{AntiTranspose, Coord{Row: 3, Col: 2}},
+	}
+	for _, tc := range tests {
+		got := tc.s.Apply(c, rows, cols)
+		if got != tc.want {
+			t.Errorf("%v.Apply(%v) = %v, want %v", tc.s, c, got, tc.want)
+		}
+		r2, c2 := rows, cols
+		if tc.s.SwapsAxes() {
+			r2, c2 = cols, rows
+		}
+		if back := tc.s.Inverse().Apply(got, r2, c2); back != c {
+			t.Errorf("%v.Apply(%v.Apply(%v)) = %v, want %v", tc.s.Inverse(), tc.s, c, back, c)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	b, err := New(sampleTiles, WithScoring(GroupScoring))
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	for _, s := range Symmetries() {
+		locs, err := TransformLayout(sampleTiles, DefaultRules(), s)
+		if err != nil {
+			t.Fatalf("TransformLayout(%v) failed: %v", s, err)
+		}
+		got := b.Transform(s)
+		if diff := cmp.Diff(locs, got.Layout()); diff != "" {
+			t.Errorf("%v: Layout() returned diff (-want +got):\n%s", s, diff)
+		}
+		if got.RedScore() != b.RedScore() || got.BlackScore() != b.BlackScore() || got.IsRedsTurn() != b.IsRedsTurn() {
+			t.Errorf("%v: scores %d-%d, red's turn %v, want %d-%d, %v", s, got.RedScore(), got.BlackScore(), got.IsRedsTurn(), b.RedScore(), b.BlackScore(), b.IsRedsTurn())
+		}
+		if got.Hash() != got.ComputeHash() {
+			t.Errorf("%v: Hash() = %x, want %x", s, got.Hash(), got.ComputeHash())
+		}
+		var want []Coord
+		for _, m := range b.LegalMoves() {
+			want = append(want, s.Apply(m, b.Rows(), b.Cols()))
+		}
+		legal := got.LegalMoves()
+		for _, moves := range [][]Coord{want, legal} {
+			sort.Slice(moves, func(i, j int) bool {
+				return moves[i].Row < moves[j].Row || moves[i].Row == moves[j].Row && moves[i].Col < moves[j].Col
+			})
+		}
+		if diff := cmp.Diff(want, legal); diff != "" {
+			t.Errorf("%v: LegalMoves() returned diff (-want +got):\n%s", s, diff)
+		}
+		if back := got.Transform(s.Inverse()); back.String() != b.String() {
+			t.Errorf("%v: transforming back returned:\n%s\nwant:\n%s", s, back, b)
+		}
+	}
+}
+
+func TestLayoutSymmetries(t *testing.T) {
+	r := Rules{TileSizes: []int{4, 2, 2, 2, 2}, MarblesPerPlayer: 6, BlockedMoves: 1}
+	tests := []struct {
+		name  string
+		locs  []TileLocation
+		rules Rules
+		want  []Symmetry
+	}{
+		{
+			name:  "sample",
+			locs:  sampleTiles,
+			rules: DefaultRules(),
+			want:  []Symmetry{Identity},
+		},
+		{
+			// A pinwheel of dominoes around a square:
+			// AA B
+			//  SSB
+			// DSS
+			// D CC
+			name: "pinwheel",
+			locs: []TileLocation{
+				{Coord: Coord{Row: 1, Col: 1}},
+				{Coord: Coord{Row: 0, Col: 0}, IsLandscape: true},
+				{Coord: Coord{Row: 0, Col: 3}},
+				{Coord: Coord{Row: 3, Col: 2}, IsLandscape: true},
+				{Coord: Coord{Row: 2, Col: 0}},
+			},
+			rules: r,
+			want:  []Symmetry{Identity, Rotate90, Rotate180, Rotate270},
+		},
+		{
+			// A square with dominoes on two sides:
+			// AA
+			// SS
+			// SSB
+			//   B
+			name: "diagonal",
+			locs: []TileLocation{
+				{Coord: Coord{Row: 1, Col: 0}},
+				{Coord: Coord{Row: 0, Col: 0}, IsLandscape: true},
+				{Coord: Coord{Row: 2, Col: 2}},
+			},
+			rules: Rules{TileSizes: []int{4, 2, 2}, MarblesPerPlayer: 4, BlockedMoves: 1},
+			want:  []Symmetry{Identity},
+		},
+		{
+			name:  "single square",
+			locs:  []TileLocation{{Coord: Coord{Row: 0, Col: 0}}},
+			rules: Rules{TileSizes: []int{4}, MarblesPerPlayer: 2},
+			want:  kSymmetries,
+		},
+	}
+	for _, tc := range tests {
+		got, err := LayoutSymmetries(tc.locs, tc.rules)
+		if err != nil {
+			t.Fatalf("%s: LayoutSymmetries() failed: %v", tc.name, err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: LayoutSymmetries() returned diff (-want +got):\n%s", tc.name, diff)
+		}
+	}
+}
+
+func TestLayoutSymmetriesGenerated(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, s := range []Symmetry{Rotate180, FlipHorizontal, FlipVertical} {
+		locs, err := GenerateLayout(rng, DefaultRules(), LayoutConstraints{Connected: true, Symmetry: s})
+		if err != nil {
+			t.Fatalf("GenerateLayout(%v) failed: %v", s, err)
+		}
+		got, err := LayoutSymmetries(locs, DefaultRules())
+		if err != nil {
+			t.Fatalf("LayoutSymmetries() failed: %v", err)
+		}
+		found := false
+		for _, g := range got {
+			found = found || g == s
+		}
+		if !found {
+			t.Errorf("LayoutSymmetries() of a layout generated with %v = %v", s, got)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	b, err := New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for i, m := range sampleMoves {
+		if err := b.Move(m, i%2 == 0); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	want, sym := b.Canonical()
+	if got := b.Transform(sym); got.String() != want.String() {
+		t.Errorf("Transform(%v) returned:\n%s\nwant the canonical form:\n%s", sym, got, want)
+	}
+	for _, s := range Symmetries() {
+		got, _ := b.Transform(s).Canonical()
+		if got.String() != want.String() || got.Hash() != want.Hash() {
+			t.Errorf("canonical form of the %v image returned:\n%s\nwant:\n%s", s, got, want)
+		}
+		if diff := cmp.Diff(want.Layout(), got.Layout()); diff != "" {
+			t.Errorf("canonical form of the %v image has layout diff (-want +got):\n%s", s, diff)
+		}
+	}
+	// A different position has a different canonical form.
+	b.UndoLastMove()
+	if got, _ := b.Canonical(); got.Hash() == want.Hash() {
+		t.Errorf("Canonical() after UndoLastMove() has the same hash")
+	}
+}
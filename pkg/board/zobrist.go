@@ -0,0 +1,78 @@
+package board
+
+import "math/rand"
+
+// Random keys for Zobrist hashing of positions.
+var (
+	zobristMarbles     [2][kMaxCells]uint64           // A red or black marble on a cell.
+	zobristBlackToMove uint64                         // Black is the side to move.
+	zobristLine        [kMaxCells]uint64              // The last move, which sets the row and column of the next.
+	zobristBlocked     [kMaxBlocked][kMaxTiles]uint64 // A tile blocked by one of the most recent moves.
+)
+
+func init() {
+	// A fixed seed keeps hashes stable across runs of the same version of
+	// these tables. Changing their shapes or order changes every hash, so
+	// stored hashes are only valid for the version that computed them.
+	r := rand.New(rand.NewSource(0x6b756c616d69))
+	for i := range zobristMarbles {
+		for j := range zobristMarbles[i] {
+			zobristMarbles[i][j] = r.Uint64()
+		}
+	}
+	zobristBlackToMove = r.Uint64()
+	for i := range zobristLine {
+		zobristLine[i] = r.Uint64()
+	}
+	for i := range zobristBlocked {
+		for j := range zobristBlocked[i] {
+			zobristBlocked[i][j] = r.Uint64()
+		}
+	}
+}
+
+// Hash returns a 64-bit Zobrist hash of the position. It covers the marble on
+// each hole, the side to move, the row and column of the last move and the
+// tiles blocked by the most recent moves, so positions with equal hashes have
+// (barring collisions) the same legal continuations.
+func (b *KulamiBoard) Hash() uint64 {
+	return b.hash
+}
+
+// ComputeHash computes the hash of the position from scratch. It always
+// equals Hash, which is maintained incrementally; use it for verification.
+func (b *KulamiBoard) ComputeHash() uint64 {
+	h := b.turnHash()
+	for i := b.red.next(0); i >= 0; i = b.red.next(i + 1) {
+		h ^= zobristMarbles[0][i]
+	}
+	for i := b.black.next(0); i >= 0; i = b.black.next(i + 1) {
+		h ^= zobristMarbles[1][i]
+	}
+	return h
+}
+
+// turnHash returns the part of the hash which depends on the order of the
+// moves rather than on the marbles: the side to move and the constraints set
+// by the most recent moves.
+func (b *KulamiBoard) turnHash() uint64 {
+	var h uint64
+	if !b.IsRedsTurn() {
+		h ^= zobristBlackToMove
+	}
+	if b.rules.RowColumn && b.numMoves > 0 {
+		h ^= zobristLine[b.moves[b.numMoves-1]]
+	}
+	for k := 1; k <= b.rules.BlockedMoves && k <= b.numMoves; k++ {
+		h ^= zobristBlocked[k-1][b.tiles[b.moves[b.numMoves-k]]]
+	}
+	return h
+}
+
+// marbleHash returns the key of a marble of the given color on cell i.
+func marbleHash(i int, isRed bool) uint64 {
+	if isRed {
+		return zobristMarbles[0][i]
+	}
+	return zobristMarbles[1][i]
+}
@@ -0,0 +1,227 @@
+// Package gametree keeps a tree of variations of a Kulami game, for analysis.
+package gametree
+
+import (
+	"fmt"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+// Node is a position in the tree, reached by a move from its parent.
+type Node struct {
+	Comment string   // Free text annotation of the move.
+	Eval    *float64 // Evaluation of the position, if any, in favor of Red.
+
+	move     board.Coord
+	isRed    bool
+	parent   *Node
+	children []*Node
+}
+
+// Move returns the move leading to the node. The root has no move.
+func (n *Node) Move() board.Coord {
+	return n.move
+}
+
+// IsRed returns whether Red made the move leading to the node.
+func (n *Node) IsRed() bool {
+	return n.isRed
+}
+
+// Parent returns the node before the move, or nil for the root.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the moves played from the node. The first one continues
+// the main line, the others are variations.
+func (n *Node) Children() []*Node {
+	return append([]*Node(nil), n.children...)
+}
+
+// Ply returns the number of moves from the root to the node.
+func (n *Node) Ply() int {
+	ply := 0
+	for ; n.parent != nil; n = n.parent {
+		ply++
+	}
+	return ply
+}
+
+// SetEval sets the evaluation of the position.
+func (n *Node) SetEval(v float64) {
+	n.Eval = &v
+}
+
+// child returns the child with the given move, or nil.
+func (n *Node) child(c board.Coord) *Node {
+	for _, ch := range n.children {
+		if ch.move == c {
+			return ch
+		}
+	}
+	return nil
+}
+
+// Tree is a game tree, starting from a given position, with a current node
+// to navigate it.
+type Tree struct {
+	start *board.KulamiBoard // The position at the root.
+	root  *Node
+	cur   *Node
+	b     *board.KulamiBoard // The position at cur.
+}
+
+// New returns a tree with only a root, at a copy of the given position.
+func New(b *board.KulamiBoard) *Tree {
+	root := &Node{}
+	return &Tree{start: b.Clone(), root: root, cur: root, b: b.Clone()}
+}
+
+// Root returns the root node.
+func (t *Tree) Root() *Node {
+	return t.root
+}
+
+// Current returns the current node.
+func (t *Tree) Current() *Node {
+	return t.cur
+}
+
+// Board returns a copy of the position at the current node.
+func (t *Tree) Board() *board.KulamiBoard {
+	return t.b.Clone()
+}
+
+// Start returns a copy of the position at the root.
+func (t *Tree) Start() *board.KulamiBoard {
+	return t.start.Clone()
+}
+
+// Play makes a move from the current node by the player to move, and goes to
+// the resulting node. If the move was already played from there, the
+// existing node is reused; otherwise it is added as the last variation.
+func (t *Tree) Play(c board.Coord) (*Node, error) {
+	return t.play(c, t.b.IsRedsTurn())
+}
+
+// play makes a move by the given player from the current node.
+func (t *Tree) play(c board.Coord, isRed bool) (*Node, error) {
+	if n := t.cur.child(c); n != nil && n.isRed == isRed {
+		t.Forward(n)
+		return n, nil
+	}
+	if err := t.b.Move(c, isRed); err != nil {
+		return nil, err
+	}
+	n := &Node{move: c, isRed: isRed, parent: t.cur}
+	t.cur.children = append(t.cur.children, n)
+	t.cur = n
+	return n, nil
+}
+
+// Forward goes to a child of the current node, or along the main line if n
+// is nil. It returns false if there is no such child.
+func (t *Tree) Forward(n *Node) bool {
+	if n == nil {
+		if len(t.cur.children) == 0 {
+			return false
+		}
+		n = t.cur.children[0]
+	}
+	if n.parent != t.cur {
+		return false
+	}
+	if err := t.b.Move(n.move, n.isRed); err != nil {
+		// Nodes are only added after their move was played successfully.
+		panic(fmt.Sprintf("replaying %v: %v", n.move, err))
+	}
+	t.cur = n
+	return true
+}
+
+// Back goes to the parent of the current node. It returns false at the root.
+func (t *Tree) Back() bool {
+	if t.cur.parent == nil {
+		return false
+	}
+	t.b.UndoLastMove()
+	t.cur = t.cur.parent
+	return true
+}
+
+// Variation goes to the i-th child of the current node.
+func (t *Tree) Variation(i int) error {
+	if i < 0 || i >= len(t.cur.children) {
+		return fmt.Errorf("no variation %d, the position has %d", i, len(t.cur.children))
+	}
+	t.Forward(t.cur.children[i])
+	return nil
+}
+
+// GoTo goes to any node of the tree.
+func (t *Tree) GoTo(n *Node) error {
+	var path []*Node
+	for m := n; m != t.root; m = m.parent {
+		if m == nil {
+			return fmt.Errorf("node is not in the tree")
+		}
+		path = append(path, m)
+	}
+	for t.Back() {
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		t.Forward(path[i])
+	}
+	return nil
+}
+
+// GoToPly goes to the given ply of the current line: back towards the root,
+// or forward along the main line from the current node.
+func (t *Tree) GoToPly(ply int) error {
+	if ply < 0 {
+		return fmt.Errorf("negative ply %d", ply)
+	}
+	n := t.cur
+	for i := n.Ply(); i > ply; i-- {
+		n = n.parent
+	}
+	for i := n.Ply(); i < ply; i++ {
+		if len(n.children) == 0 {
+			return fmt.Errorf("the line ends at ply %d", i)
+		}
+		n = n.children[0]
+	}
+	return t.GoTo(n)
+}
+
+// Promote makes the line leading to n the main line, by making it and each
+// of its ancestors the first child of its parent.
+func (t *Tree) Promote(n *Node) error {
+	for m := n; m != t.root; m = m.parent {
+		if m == nil {
+			return fmt.Errorf("node is not in the tree")
+		}
+	}
+	for ; n.parent != nil; n = n.parent {
+		siblings := n.parent.children
+		for i, s := range siblings {
+			if s == n {
+				copy(siblings[1:i+1], siblings[:i])
+				siblings[0] = n
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// MainLine returns the moves of the main line from the root.
+func (t *Tree) MainLine() []board.Coord {
+	var res []board.Coord
+	for n := t.root; len(n.children) > 0; {
+		n = n.children[0]
+		res = append(res, n.move)
+	}
+	return res
+}
@@ -0,0 +1,205 @@
+package gametree
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+var sampleTiles = []board.TileLocation{
+	{Coord: board.Coord{Row: 4, Col: 0}},
+	{Coord: board.Coord{Row: 6, Col: 2}, IsLandscape: true},
+	{Coord: board.Coord{Row: 4, Col: 3}, IsLandscape: true},
+	{Coord: board.Coord{Row: 1, Col: 6}},
+	{Coord: board.Coord{Row: 0, Col: 4}},
+	{Coord: board.Coord{Row: 2, Col: 4}},
+	{Coord: board.Coord{Row: 2, Col: 2}},
+	{Coord: board.Coord{Row: 4, Col: 6}},
+	{Coord: board.Coord{Row: 7, Col: 5}},
+	{Coord: board.Coord{Row: 1, Col: 1}, IsLandscape: true},
+	{Coord: board.Coord{Row: 2, Col: 8}},
+	{Coord: board.Coord{Row: 5, Col: 8}, IsLandscape: true},
+	{Coord: board.Coord{Row: 6, Col: 5}, IsLandscape: true},
+	{Coord: board.Coord{Row: 4, Col: 2}},
+	{Coord: board.Coord{Row: 4, Col: 9}, IsLandscape: true},
+	{Coord: board.Coord{Row: 2, Col: 0}},
+	{Coord: board.Coord{Row: 2, Col: 1}},
+}
+
+// Moves of the sample tree: mainN is the Nth move of the main line, altN of
+// the variation.
+var (
+	main1 = board.Coord{Row: 4, Col: 5} // f5
+	main2 = board.Coord{Row: 4, Col: 0} // a5
+	main3 = board.Coord{Row: 2, Col: 0} // a3
+	alt2  = board.Coord{Row: 4, Col: 7} // h5
+	alt3  = board.Coord{Row: 2, Col: 7} // h3
+)
+
+// newSampleTree returns a tree with the main line f5 a5 a3 and the variation
+// f5 h5 h3, at the end of the variation.
+func newSampleTree(t *testing.T) *Tree {
+	t.Helper()
+	b, err := board.New(sampleTiles)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	tree := New(b)
+	for _, m := range []board.Coord{main1, main2, main3} {
+		if _, err := tree.Play(m); err != nil {
+			t.Fatalf("Play(%v) failed: %v", m, err)
+		}
+	}
+	if err := tree.GoToPly(1); err != nil {
+		t.Fatalf("GoToPly(1) failed: %v", err)
+	}
+	for _, m := range []board.Coord{alt2, alt3} {
+		if _, err := tree.Play(m); err != nil {
+			t.Fatalf("Play(%v) failed: %v", m, err)
+		}
+	}
+	return tree
+}
+
+func TestNavigation(t *testing.T) {
+	tree := newSampleTree(t)
+	if diff := cmp.Diff([]board.Coord{main1, main2, main3}, tree.MainLine()); diff != "" {
+		t.Errorf("MainLine() returned diff (-want +got):\n%s", diff)
+	}
+	cur := tree.Current()
+	if cur.Move() != alt3 || cur.Ply() != 3 || !cur.IsRed() {
+		t.Errorf("Current() = %v at ply %d, red %v, want %v at ply 3 by red", cur.Move(), cur.Ply(), cur.IsRed(), alt3)
+	}
+	if got := tree.Board().Moves(); !cmp.Equal(got, []board.Coord{main1, alt2, alt3}) {
+		t.Errorf("Board().Moves() = %v, want %v", got, []board.Coord{main1, alt2, alt3})
+	}
+
+	// Back to the fork, then into the main line.
+	if !tree.Back() || !tree.Back() {
+		t.Fatalf("Back() failed")
+	}
+	if got := len(tree.Current().Children()); got != 2 {
+		t.Errorf("the fork has %d children, want 2", got)
+	}
+	if !tree.Forward(nil) || tree.Current().Move() != main2 {
+		t.Errorf("Forward(nil) went to %v, want %v", tree.Current().Move(), main2)
+	}
+	tree.Back()
+	if err := tree.Variation(1); err != nil || tree.Current().Move() != alt2 {
+		t.Errorf("Variation(1) = %v, went to %v, want %v", err, tree.Current().Move(), alt2)
+	}
+	if err := tree.Variation(1); err == nil {
+		t.Errorf("Variation(1) without children succeeded")
+	}
+
+	// Replaying an existing move reuses its node.
+	tree.Back()
+	n, err := tree.Play(main2)
+	if err != nil {
+		t.Fatalf("Play(%v) failed: %v", main2, err)
+	}
+	if n != tree.Root().Children()[0].Children()[0] {
+		t.Errorf("Play(%v) added a new node", main2)
+	}
+
+	if err := tree.GoToPly(3); err != nil || tree.Current().Move() != main3 {
+		t.Errorf("GoToPly(3) = %v, went to %v, want %v", err, tree.Current().Move(), main3)
+	}
+	if err := tree.GoToPly(4); err == nil {
+		t.Errorf("GoToPly(4) past the end of the line succeeded")
+	}
+	if err := tree.GoToPly(0); err != nil || tree.Current() != tree.Root() || tree.Board().NumMoves() != 0 {
+		t.Errorf("GoToPly(0) = %v, want the root", err)
+	}
+	if tree.Back() {
+		t.Errorf("Back() at the root succeeded")
+	}
+
+	variation := tree.Root().Children()[0].Children()[1].Children()[0]
+	if err := tree.GoTo(variation); err != nil {
+		t.Fatalf("GoTo() failed: %v", err)
+	}
+	if got := tree.Board().Moves(); !cmp.Equal(got, []board.Coord{main1, alt2, alt3}) {
+		t.Errorf("Board().Moves() after GoTo() = %v, want %v", got, []board.Coord{main1, alt2, alt3})
+	}
+	if err := tree.GoTo(&Node{}); err == nil {
+		t.Errorf("GoTo() a foreign node succeeded")
+	}
+
+	if _, err := tree.Play(alt3); err == nil {
+		t.Errorf("Play() of an occupied hole succeeded")
+	}
+}
+
+func TestPromote(t *testing.T) {
+	tree := newSampleTree(t)
+	if err := tree.Promote(tree.Current()); err != nil {
+		t.Fatalf("Promote() failed: %v", err)
+	}
+	if diff := cmp.Diff([]board.Coord{main1, alt2, alt3}, tree.MainLine()); diff != "" {
+		t.Errorf("MainLine() after Promote() returned diff (-want +got):\n%s", diff)
+	}
+	if got := tree.Root().Children()[0].Children()[1].Move(); got != main2 {
+		t.Errorf("the demoted variation starts with %v, want %v", got, main2)
+	}
+	if err := tree.Promote(&Node{parent: &Node{}}); err == nil {
+		t.Errorf("Promote() of a foreign node succeeded")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tree := newSampleTree(t)
+	tree.Current().Comment = "the variation"
+	tree.Root().Children()[0].SetEval(1.5)
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	var got Tree
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	if diff := cmp.Diff(tree.MainLine(), got.MainLine()); diff != "" {
+		t.Errorf("MainLine() returned diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(tree.Board().Moves(), got.Board().Moves()); diff != "" {
+		t.Errorf("the current position has move diff (-want +got):\n%s", diff)
+	}
+	if got.Current().Comment != "the variation" {
+		t.Errorf("Current().Comment = %q, want %q", got.Current().Comment, "the variation")
+	}
+	if e := got.Root().Children()[0].Eval; e == nil || *e != 1.5 {
+		t.Errorf("Eval = %v, want 1.5", e)
+	}
+	again, err := json.Marshal(&got)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("JSON round trip returned:\n%s\nwant:\n%s", again, data)
+	}
+
+	bad := []string{
+		`{"root": {}}`,
+		`{"start": ` + string(mustMarshal(t, tree.Start())) + `, "root": {"children": [{"move": {"row": 0, "col": 0}, "player": "red"}]}}`,
+		`{"start": ` + string(mustMarshal(t, tree.Start())) + `, "root": {"children": [{"move": {"row": 4, "col": 5}, "player": "blue"}]}}`,
+		`{"start": ` + string(mustMarshal(t, tree.Start())) + `, "root": {}, "current": [0]}`,
+	}
+	for _, in := range bad {
+		var tr Tree
+		if err := json.Unmarshal([]byte(in), &tr); err == nil {
+			t.Errorf("json.Unmarshal(%s) succeeded", in)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,105 @@
+package gametree
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+// jsonTree is the JSON form of a tree.
+type jsonTree struct {
+	Start   *board.KulamiBoard `json:"start"`
+	Root    jsonNode           `json:"root"`
+	Current []int              `json:"current,omitempty"` // Child indices from the root to the current node.
+}
+
+type jsonNode struct {
+	Move     *board.Coord `json:"move,omitempty"`
+	Player   string       `json:"player,omitempty"`
+	Comment  string       `json:"comment,omitempty"`
+	Eval     *float64     `json:"eval,omitempty"`
+	Children []jsonNode   `json:"children,omitempty"`
+}
+
+func playerName(isRed bool) string {
+	if isRed {
+		return "red"
+	}
+	return "black"
+}
+
+func toJSON(n *Node, isRoot bool) jsonNode {
+	res := jsonNode{Comment: n.Comment, Eval: n.Eval}
+	if !isRoot {
+		m := n.move
+		res.Move, res.Player = &m, playerName(n.isRed)
+	}
+	for _, ch := range n.children {
+		res.Children = append(res.Children, toJSON(ch, false))
+	}
+	return res
+}
+
+// MarshalJSON encodes the tree as a JSON object with the start position, the
+// nested nodes and the path to the current node.
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	res := jsonTree{Start: t.start, Root: toJSON(t.root, true)}
+	for n := t.cur; n.parent != nil; n = n.parent {
+		for i, s := range n.parent.children {
+			if s == n {
+				res.Current = append([]int{i}, res.Current...)
+			}
+		}
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON rebuilds a tree from a JSON object in the format of
+// MarshalJSON, replaying all moves. Illegal moves are rejected.
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	var in jsonTree
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Start == nil {
+		return fmt.Errorf("missing start position")
+	}
+	res := New(in.Start)
+	res.root.Comment, res.root.Eval = in.Root.Comment, in.Root.Eval
+	if err := res.addChildren(in.Root.Children); err != nil {
+		return err
+	}
+	for _, i := range in.Current {
+		if err := res.Variation(i); err != nil {
+			return fmt.Errorf("current node: %v", err)
+		}
+	}
+	*t = *res
+	return nil
+}
+
+// addChildren adds the children of the current node, and their subtrees.
+func (t *Tree) addChildren(children []jsonNode) error {
+	for _, ch := range children {
+		if ch.Move == nil {
+			return fmt.Errorf("ply %d: missing move", t.cur.Ply()+1)
+		}
+		if ch.Player != "red" && ch.Player != "black" {
+			return fmt.Errorf("ply %d: unknown player %q", t.cur.Ply()+1, ch.Player)
+		}
+		if t.cur.child(*ch.Move) != nil {
+			return fmt.Errorf("ply %d: duplicate move %v", t.cur.Ply()+1, *ch.Move)
+		}
+		n, err := t.play(*ch.Move, ch.Player == "red")
+		if err != nil {
+			return fmt.Errorf("ply %d: %v", t.cur.Ply()+1, err)
+		}
+		n.Comment, n.Eval = ch.Comment, ch.Eval
+		if err := t.addChildren(ch.Children); err != nil {
+			return err
+		}
+		t.Back()
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+const kDefaultFrameDelay = time.Second
+
+// MaxFrameDelay is the longest FrameDelay. GIF stores delays in hundredths
+// of a second in 16 bits, which has to hold the longer final delay too.
+const MaxFrameDelay = 65535 / 3 * 10 * time.Millisecond
+
+// FrameDelay sets how long each position of a GIF animation is shown, between
+// 0 and MaxFrameDelay. The default is a second. The final position is shown
+// three times as long.
+func FrameDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.frameDelay = d
+	}
+}
+
+// gifPalette has all colors of images, so that frames are exact.
+func gifPalette() color.Palette {
+	p := color.Palette{backgroundColor, outlineColor, holeColor, legalColor, markerColor, highlightColor, labelColor}
+	for _, c := range []board.Marble{board.RedMarble, board.BlackMarble} {
+		p = append(p, marbleFills[c])
+	}
+	for _, c := range []board.Marble{board.EmptyHole, board.RedMarble, board.BlackMarble} {
+		p = append(p, tileFills[c][0], tileFills[c][1])
+	}
+	return p
+}
+
+// GIF writes an animation of the game played on the board, replaying its
+// moves from the start: one frame per ply, each drawn like Image with a
+// caption of the scores. Set-up moves are part of the first frame.
+func GIF(w io.Writer, b *board.KulamiBoard, opts ...Option) error {
+	o := options{frameDelay: kDefaultFrameDelay}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.frameDelay < 0 || o.frameDelay > MaxFrameDelay {
+		return fmt.Errorf("frame delay %v is not between 0 and %v", o.frameDelay, MaxFrameDelay)
+	}
+	o.caption = true
+	delay := int(o.frameDelay / (10 * time.Millisecond))
+
+	moves := b.Moves()
+	replay := b.Clone()
+	for replay.NumMoves() > b.SetupMoves() {
+		replay.UndoLastMove()
+	}
+	palette := gifPalette()
+	anim := &gif.GIF{}
+	addFrame := func() {
+		img := image.NewRGBA(image.Rectangle{Max: newGeometry(replay, o).size()})
+		drawBoard(&imageCanvas{img}, replay, o)
+		frame := image.NewPaletted(img.Bounds(), palette)
+		draw.Draw(frame, frame.Rect, img, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	addFrame()
+	for _, m := range moves[replay.NumMoves():] {
+		if err := replay.Move(m, b.MarbleAt(m) == board.RedMarble); err != nil {
+			return err
+		}
+		addFrame()
+	}
+	anim.Delay[len(anim.Delay)-1] *= 3
+	return gif.EncodeAll(w, anim)
+}
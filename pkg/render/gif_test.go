@@ -0,0 +1,85 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+func TestGIF(t *testing.T) {
+	b := newSampleBoard(t, len(sampleMoves))
+	hash := b.Hash()
+	var out bytes.Buffer
+	if err := GIF(&out, b, FrameDelay(500*time.Millisecond), CellSize(20)); err != nil {
+		t.Fatalf("GIF() failed: %v", err)
+	}
+	anim, err := gif.DecodeAll(&out)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() failed: %v", err)
+	}
+	if got, want := len(anim.Image), len(sampleMoves)+1; got != want {
+		t.Fatalf("GIF() has %d frames, want %d", got, want)
+	}
+	if anim.Delay[0] != 50 || anim.Delay[len(anim.Delay)-1] != 150 {
+		t.Errorf("GIF() delays = %v, want 50 and 150 for the last frame", anim.Delay)
+	}
+	g := newGeometry(b, options{cellSize: 20, caption: true})
+	if got, want := anim.Image[0].Bounds().Max, g.size(); got != want {
+		t.Errorf("GIF() frame size = %v, want %v", got, want)
+	}
+	for ply, img := range anim.Image {
+		if ply == 0 {
+			continue
+		}
+		at := g.center(b.Moves()[ply-1])
+		if got := img.At(at.X, at.Y); got != markerColor {
+			t.Errorf("GIF() frame %d at the move %v = %v, want the marker", ply, b.Moves()[ply-1], got)
+		}
+	}
+	if b.Hash() != hash || b.NumMoves() != len(sampleMoves) {
+		t.Errorf("GIF() changed the board")
+	}
+
+	// A position set up from a diagram starts with all set-up moves.
+	setup, err := board.ParseDiagram(b.String(), board.DefaultRules())
+	if err != nil {
+		t.Fatalf("ParseDiagram() failed: %v", err)
+	}
+	if err := setup.Move(setup.LegalMoves()[0], setup.IsRedsTurn()); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+	out.Reset()
+	if err := GIF(&out, setup); err != nil {
+		t.Fatalf("GIF() failed: %v", err)
+	}
+	if anim, err = gif.DecodeAll(&out); err != nil {
+		t.Fatalf("gif.DecodeAll() failed: %v", err)
+	}
+	if len(anim.Image) != 2 || anim.Delay[0] != 100 {
+		t.Errorf("GIF() of a set-up position has %d frames with delays %v, want 2 from 100", len(anim.Image), anim.Delay)
+	}
+}
+
+func TestGIFFrameDelay(t *testing.T) {
+	b := newSampleBoard(t, 2)
+	for _, d := range []time.Duration{-time.Millisecond, MaxFrameDelay + 10*time.Millisecond, time.Hour} {
+		var out bytes.Buffer
+		if err := GIF(&out, b, FrameDelay(d), CellSize(10)); err == nil || out.Len() != 0 {
+			t.Errorf("GIF() with delay %v = %v after %d bytes, want an error before writing", d, err, out.Len())
+		}
+	}
+	var out bytes.Buffer
+	if err := GIF(&out, b, FrameDelay(MaxFrameDelay), CellSize(10)); err != nil {
+		t.Fatalf("GIF() with delay %v failed: %v", MaxFrameDelay, err)
+	}
+	anim, err := gif.DecodeAll(&out)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() failed: %v", err)
+	}
+	if got, want := anim.Delay[len(anim.Delay)-1], 3*int(MaxFrameDelay/(10*time.Millisecond)); got != want {
+		t.Errorf("GIF() with delay %v ends with delay %d, want %d", MaxFrameDelay, got, want)
+	}
+}
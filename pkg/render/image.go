@@ -0,0 +1,254 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+const kDefaultCellSize = 40
+
+// CellSize sets the size of a hole of the board in SVG and PNG images, in
+// pixels. The default is 40.
+func CellSize(px int) Option {
+	return func(o *options) {
+		o.cellSize = px
+	}
+}
+
+// Caption adds the scores below the board in SVG and PNG images, or the
+// result once the game is over.
+func Caption() Option {
+	return func(o *options) {
+		o.caption = true
+	}
+}
+
+// Colors of images. Tiles and marbles match those of Text with Color.
+var (
+	backgroundColor = color.RGBA{0xfa, 0xf7, 0xf0, 0xff}
+	outlineColor    = color.RGBA{0x3a, 0x2a, 0x1a, 0xff}
+	holeColor       = color.RGBA{0x6b, 0x4f, 0x33, 0xff}
+	legalColor      = color.RGBA{0x00, 0x87, 0x00, 0xff}
+	markerColor     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	highlightColor  = color.RGBA{0xff, 0xd7, 0x00, 0xff}
+	labelColor      = color.RGBA{0x30, 0x30, 0x30, 0xff}
+	marbleFills     = map[board.Marble]color.RGBA{
+		board.RedMarble:   {0xd7, 0x00, 0x00, 0xff},
+		board.BlackMarble: {0x1c, 0x1c, 0x1c, 0xff},
+	}
+	// Open tiles first, then blocked ones.
+	tileFills = map[board.Marble][2]color.RGBA{
+		board.EmptyHole:   {{0xd7, 0xaf, 0x87, 0xff}, {0xaf, 0x87, 0x5f, 0xff}},
+		board.RedMarble:   {{0xff, 0xaf, 0xaf, 0xff}, {0xd7, 0x87, 0x87, 0xff}},
+		board.BlackMarble: {{0xd0, 0xd0, 0xd0, 0xff}, {0x94, 0x94, 0x94, 0xff}},
+	}
+)
+
+// canvas is what a board is drawn on. All coordinates are in pixels.
+type canvas interface {
+	// rect fills r, with an outline of the given width inside it.
+	rect(r image.Rectangle, fill color.RGBA, outline int)
+	// circle fills a circle; class tells what it shows.
+	circle(c image.Point, radius int, fill color.RGBA, class string)
+	// text writes s centered at c, with letters size pixels high.
+	text(c image.Point, size int, s string)
+}
+
+// geometry is the placement of the board in an image.
+type geometry struct {
+	cell, margin, caption int
+	rows, cols            int
+}
+
+func newGeometry(b *board.KulamiBoard, o options) geometry {
+	g := geometry{cell: o.cellSize, rows: b.Rows(), cols: b.Cols()}
+	if g.cell <= 0 {
+		g.cell = kDefaultCellSize
+	}
+	g.margin = g.cell / 2
+	if o.caption {
+		g.caption = g.cell
+	}
+	return g
+}
+
+func (g geometry) size() image.Point {
+	return image.Pt(2*g.margin+g.cols*g.cell, 2*g.margin+g.rows*g.cell+g.caption)
+}
+
+// center returns the center of a hole.
+func (g geometry) center(c board.Coord) image.Point {
+	return image.Pt(g.margin+c.Col*g.cell+g.cell/2, g.margin+c.Row*g.cell+g.cell/2)
+}
+
+// drawBoard draws the tiles, marbles, markers and labels of the board.
+func drawBoard(cv canvas, b *board.KulamiBoard, o options) {
+	g := newGeometry(b, o)
+	size := g.size()
+	cv.rect(image.Rect(0, 0, size.X, size.Y), backgroundColor, 0)
+	blocked := make(map[int]bool)
+	for _, t := range b.BlockedTiles() {
+		blocked[t] = true
+	}
+	gap, outline := maxInt(1, g.cell/16), maxInt(1, g.cell/20)
+	for t := 0; t < b.NumTiles(); t++ {
+		cells := b.TileCells(t)
+		first, last := g.center(cells[0]), g.center(cells[len(cells)-1])
+		r := image.Rect(first.X-g.cell/2+gap, first.Y-g.cell/2+gap, last.X+g.cell/2-gap, last.Y+g.cell/2-gap)
+		fill := tileFills[b.TileOwner(t)][0]
+		if blocked[t] {
+			fill = tileFills[b.TileOwner(t)][1]
+		}
+		cv.rect(r, fill, outline)
+	}
+
+	legal := make(map[board.Coord]bool)
+	if o.legal {
+		for _, m := range b.LegalMoves() {
+			legal[m] = true
+		}
+	}
+	moves := b.Moves()
+	if len(moves) > 0 {
+		cv.circle(g.center(moves[len(moves)-1]), g.cell*7/16, highlightColor, "highlight")
+	}
+	for row := 0; row < g.rows; row++ {
+		for col := 0; col < g.cols; col++ {
+			c := board.Coord{Row: row, Col: col}
+			switch m := b.MarbleAt(c); m {
+			case board.EmptyHole:
+				if legal[c] {
+					cv.circle(g.center(c), g.cell/6, legalColor, "legal")
+				} else {
+					cv.circle(g.center(c), g.cell/6, holeColor, "hole")
+				}
+			case board.RedMarble, board.BlackMarble:
+				cv.circle(g.center(c), g.cell*3/8, marbleFills[m], m.String())
+			}
+		}
+	}
+	// Mark the last two moves, like Text does with capitals. The last one
+	// is also ringed above.
+	for i := len(moves) - 1; i >= 0 && i >= len(moves)-2; i-- {
+		cv.circle(g.center(moves[i]), maxInt(1, g.cell/10), markerColor, "last")
+	}
+
+	labelSize := g.cell / 4
+	for col := 0; col < g.cols; col++ {
+		c := g.center(board.Coord{Col: col})
+		cv.text(image.Pt(c.X, g.margin/2), labelSize, string(rune('a'+col)))
+	}
+	for row := 0; row < g.rows; row++ {
+		c := g.center(board.Coord{Row: row})
+		cv.text(image.Pt(g.margin/2, c.Y), labelSize, fmt.Sprint(row+1))
+	}
+	if o.caption {
+		caption := fmt.Sprintf("Red: %d   Black: %d", b.RedScore(), b.BlackScore())
+		if b.IsGameOver() {
+			caption = b.Result().String()
+		}
+		cv.text(image.Pt(size.X/2, size.Y-(g.margin+g.caption)/2), g.cell/3, caption)
+	}
+}
+
+// SVG writes the board as an SVG image.
+func SVG(w io.Writer, b *board.KulamiBoard, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	size := newGeometry(b, o).size()
+	cv := &svgCanvas{}
+	fmt.Fprintf(&cv.res, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", size.X, size.Y, size.X, size.Y)
+	drawBoard(cv, b, o)
+	cv.res.WriteString("</svg>\n")
+	_, err := io.WriteString(w, cv.res.String())
+	return err
+}
+
+type svgCanvas struct {
+	res strings.Builder
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (cv *svgCanvas) rect(r image.Rectangle, fill color.RGBA, outline int) {
+	if outline == 0 {
+		fmt.Fprintf(&cv.res, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", r.Min.X, r.Min.Y, r.Dx(), r.Dy(), hexColor(fill))
+		return
+	}
+	// SVG strokes are centered on the edge, so move them inside.
+	half := float64(outline) / 2
+	fmt.Fprintf(&cv.res, "<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%d\"/>\n",
+		float64(r.Min.X)+half, float64(r.Min.Y)+half, float64(r.Dx()-outline), float64(r.Dy()-outline), hexColor(fill), hexColor(outlineColor), outline)
+}
+
+func (cv *svgCanvas) circle(c image.Point, radius int, fill color.RGBA, class string) {
+	fmt.Fprintf(&cv.res, "<circle class=\"%s\" cx=\"%d\" cy=\"%d\" r=\"%d\" fill=\"%s\"/>\n", class, c.X, c.Y, radius, hexColor(fill))
+}
+
+func (cv *svgCanvas) text(c image.Point, size int, s string) {
+	fmt.Fprintf(&cv.res, "<text x=\"%d\" y=\"%d\" font-family=\"monospace\" font-size=\"%d\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\">", c.X, c.Y, size, hexColor(labelColor))
+	xml.EscapeText(&cv.res, []byte(s))
+	cv.res.WriteString("</text>\n")
+}
+
+// Image draws the board on a new image, the one PNG encodes.
+func Image(b *board.KulamiBoard, opts ...Option) *image.RGBA {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cv := &imageCanvas{image.NewRGBA(image.Rectangle{Max: newGeometry(b, o).size()})}
+	drawBoard(cv, b, o)
+	return cv.img
+}
+
+// PNG writes the board as a PNG image.
+func PNG(w io.Writer, b *board.KulamiBoard, opts ...Option) error {
+	return png.Encode(w, Image(b, opts...))
+}
+
+type imageCanvas struct {
+	img *image.RGBA
+}
+
+func (cv *imageCanvas) rect(r image.Rectangle, fill color.RGBA, outline int) {
+	if outline > 0 {
+		draw.Draw(cv.img, r, image.NewUniform(outlineColor), image.Point{}, draw.Src)
+		r = r.Inset(outline)
+	}
+	draw.Draw(cv.img, r, image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+func (cv *imageCanvas) circle(c image.Point, radius int, fill color.RGBA, class string) {
+	for y := -radius; y < radius; y++ {
+		for x := -radius; x < radius; x++ {
+			// Test the centers of pixels, for a symmetric circle.
+			if (2*x+1)*(2*x+1)+(2*y+1)*(2*y+1) <= 4*radius*radius {
+				cv.img.SetRGBA(c.X+x, c.Y+y, fill)
+			}
+		}
+	}
+}
+
+func (cv *imageCanvas) text(c image.Point, size int, s string) {
+	drawText(cv.img, c, size, s, labelColor)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+func TestSVG(t *testing.T) {
+	b := newSampleBoard(t, len(sampleMoves))
+	var out bytes.Buffer
+	if err := SVG(&out, b, LegalMoves(), Caption()); err != nil {
+		t.Fatalf("SVG() failed: %v", err)
+	}
+	svg := out.String()
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG() returned invalid XML: %v\n%s", err, svg)
+		}
+	}
+	counts := []struct {
+		class string
+		want  int
+	}{
+		{"red", 7},
+		{"black", 7},
+		{"last", 2},
+		{"legal", len(b.LegalMoves())},
+		{"hole", 64 - 14 - len(b.LegalMoves())},
+	}
+	for _, c := range counts {
+		if got := strings.Count(svg, `class="`+c.class+`"`); got != c.want {
+			t.Errorf("SVG() has %d circles of class %s, want %d", got, c.class, c.want)
+		}
+	}
+	if want := "Red: 11   Black: 10"; !strings.Contains(svg, want) {
+		t.Errorf("SVG() does not contain the caption %q", want)
+	}
+	if !strings.Contains(svg, `width="480" height="440"`) {
+		t.Errorf("SVG() is not 480x440:\n%s", svg)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	b := newSampleBoard(t, len(sampleMoves))
+	var out bytes.Buffer
+	if err := PNG(&out, b, LegalMoves()); err != nil {
+		t.Fatalf("PNG() failed: %v", err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatalf("png.Decode() failed: %v", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 480, 400); got != want {
+		t.Errorf("PNG() bounds = %v, want %v", got, want)
+	}
+	g := newGeometry(b, options{})
+	pixels := []struct {
+		c     string
+		color interface{}
+	}{
+		{"f5", marbleFills[board.RedMarble]},
+		{"a5", marbleFills[board.BlackMarble]},
+		{"f8", markerColor},
+		{"f6", markerColor},
+		{"e1", holeColor},
+		{"f1", legalColor},
+	}
+	for _, p := range pixels {
+		c, err := board.ParseCoord(p.c)
+		if err != nil {
+			t.Fatalf("ParseCoord(%q) failed: %v", p.c, err)
+		}
+		at := g.center(c)
+		if got := img.At(at.X, at.Y); got != p.color {
+			t.Errorf("PNG() at %v = %v, want %v", c, got, p.color)
+		}
+	}
+
+	small := Image(b, CellSize(20), Caption())
+	if got, want := small.Bounds(), image.Rect(0, 0, 240, 220); got != want {
+		t.Errorf("Image() with CellSize(20) bounds = %v, want %v", got, want)
+	}
+}
+
+func TestFont(t *testing.T) {
+	results := []board.Result{
+		{Outcome: board.RedWins, Reason: board.OutOfMarbles, RedScore: 12, BlackScore: 10},
+		{Outcome: board.BlackWins, Reason: board.Resignation},
+		{Outcome: board.RedWins, Reason: board.TimeForfeit},
+		{Outcome: board.Draw, Reason: board.NoLegalMoves, RedScore: 9, BlackScore: 9},
+	}
+	for _, r := range results {
+		for _, c := range r.String() {
+			if _, ok := glyphs[unicode.ToUpper(c)]; !ok {
+				t.Errorf("the font has no %q, for %q", c, r)
+			}
+		}
+	}
+}
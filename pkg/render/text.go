@@ -0,0 +1,179 @@
+// Package render draws Kulami positions for people to look at.
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+// Option configures the rendering of a board.
+type Option func(*options)
+
+type options struct {
+	color      bool
+	legal      bool
+	lastLines  bool
+	cellSize   int
+	caption    bool
+	frameDelay time.Duration
+}
+
+// Color adds ANSI escape codes to the text: marbles in their colors, tiles
+// shaded by their owner, and the tiles blocked by the last moves shaded
+// darker. Without it, the text is plain ASCII.
+func Color() Option {
+	return func(o *options) {
+		o.color = true
+	}
+}
+
+// LegalMoves marks the holes where the next move may be played, with a + in
+// text and in green in images.
+func LegalMoves() Option {
+	return func(o *options) {
+		o.legal = true
+	}
+}
+
+// LastMoveLines highlights the row and the column of the last move, which
+// the next move has to be in under the row/column rule. In plain text, the
+// column letter is capitalized and the row number is marked with a >.
+func LastMoveLines() Option {
+	return func(o *options) {
+		o.lastLines = true
+	}
+}
+
+// IsTerminal returns whether f is a character device, such as a terminal,
+// which can display Color output.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+const (
+	ansiReset     = "\x1b[0m"
+	ansiReverse   = "\x1b[7m"
+	ansiUnderline = "\x1b[4m"
+	ansiLegal     = "\x1b[1;38;5;28m"
+)
+
+// Foreground colors of marbles, from the 256 color palette.
+var marbleColors = map[board.Marble]string{
+	board.RedMarble:   "\x1b[1;38;5;160m",
+	board.BlackMarble: "\x1b[1;38;5;16m",
+}
+
+// Background colors of tiles by owner, from the 256 color palette: open
+// tiles first, then blocked ones.
+var tileColors = map[board.Marble][2]int{
+	board.EmptyHole:   {180, 137},
+	board.RedMarble:   {217, 174},
+	board.BlackMarble: {252, 246},
+}
+
+// Text renders the board in the format of KulamiBoard.String, which it
+// returns exactly if no options are given.
+func Text(b *board.KulamiBoard, opts ...Option) string {
+	r := &textRenderer{b: b, last: board.Coord{Row: -1, Col: -1}}
+	for _, opt := range opts {
+		opt(&r.options)
+	}
+	if moves := b.Moves(); len(moves) > 0 {
+		r.last = moves[len(moves)-1]
+	}
+	if r.legal {
+		r.legalMoves = make(map[board.Coord]bool)
+		for _, m := range b.LegalMoves() {
+			r.legalMoves[m] = true
+		}
+	}
+	r.blocked = make(map[int]bool)
+	for _, t := range b.BlockedTiles() {
+		r.blocked[t] = true
+	}
+	return b.Diagram(r.paintPiece)
+}
+
+type textRenderer struct {
+	options
+	b          *board.KulamiBoard
+	last       board.Coord
+	legalMoves map[board.Coord]bool
+	blocked    map[int]bool
+}
+
+// paint returns s in the given ANSI style, if rendering in color.
+func (r *textRenderer) paint(s, style string) string {
+	if r.color && style != "" {
+		return style + s + ansiReset
+	}
+	return s
+}
+
+// shade returns the style of the background of tile t, or "" for no tile.
+func (r *textRenderer) shade(t int) string {
+	if t < 0 {
+		return ""
+	}
+	c := tileColors[r.b.TileOwner(t)]
+	if r.blocked[t] {
+		return fmt.Sprintf("\x1b[48;5;%dm", c[1])
+	}
+	return fmt.Sprintf("\x1b[48;5;%dm", c[0])
+}
+
+// paintPiece styles a piece of the diagram of the board.
+func (r *textRenderer) paintPiece(p board.DiagramPiece) string {
+	c := p.Coord
+	switch p.Part {
+	case board.DiagramColumnLabel:
+		if r.lastLines && c.Col == r.last.Col {
+			return r.paint(strings.ToUpper(p.Text), ansiReverse)
+		}
+	case board.DiagramRowLabel:
+		if r.lastLines && c.Row == r.last.Row {
+			return r.paint(fmt.Sprintf("%-3d", c.Row+1), ansiReverse) + ">"
+		}
+	case board.DiagramBorder:
+		// Inside a tile, the spaces are shaded like it.
+		if t := r.b.TileAt(c); strings.TrimSpace(p.Text) == "" && c.Row != 0 && t == r.b.TileAt(board.Coord{Row: c.Row - 1, Col: c.Col}) {
+			return r.paint(p.Text, r.shade(t))
+		}
+	case board.DiagramSeparator:
+		if p.Text == " " {
+			if r.lastLines && c.Row == r.last.Row {
+				return r.paint(p.Text, r.style(c)) // Underline the whole row.
+			}
+			return r.paint(p.Text, r.shade(r.b.TileAt(c)))
+		}
+	case board.DiagramHole:
+		style := r.style(c)
+		m, fg := p.Text[1:2], ""
+		switch marble := r.b.MarbleAt(c); marble {
+		case board.EmptyHole:
+			if r.legalMoves[c] {
+				m, fg = "+", ansiLegal
+			}
+		case board.RedMarble, board.BlackMarble:
+			fg = marbleColors[marble]
+		}
+		return r.paint(" ", style) + r.paint(m, style+fg) + r.paint(" ", style)
+	}
+	return p.Text
+}
+
+// style returns the style of a hole: the shade of its tile, underlined in the
+// row and the column of the last move.
+func (r *textRenderer) style(c board.Coord) string {
+	t := r.b.TileAt(c)
+	style := r.shade(t)
+	if r.lastLines && (c.Row == r.last.Row || c.Col == r.last.Col) && t >= 0 {
+		style += ansiUnderline
+	}
+	return style
+}
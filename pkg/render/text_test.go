@@ -0,0 +1,91 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ola-rozenfeld/kulami/pkg/board"
+)
+
+var sampleTiles = []board.TileLocation{
+	{Coord: board.Coord{Row: 4, Col: 0}},
+	{Coord: board.Coord{Row: 6, Col: 2}, IsLandscape: true},
+	{Coord: board.Coord{Row: 4, Col: 3}, IsLandscape: true},
+	{Coord: board.Coord{Row: 1, Col: 6}},
+	{Coord: board.Coord{Row: 0, Col: 4}},
+	{Coord: board.Coord{Row: 2, Col: 4}},
+	{Coord: board.Coord{Row: 2, Col: 2}},
+	{Coord: board.Coord{Row: 4, Col: 6}},
+	{Coord: board.Coord{Row: 7, Col: 5}},
+	{Coord: board.Coord{Row: 1, Col: 1}, IsLandscape: true},
+	{Coord: board.Coord{Row: 2, Col: 8}},
+	{Coord: board.Coord{Row: 5, Col: 8}, IsLandscape: true},
+	{Coord: board.Coord{Row: 6, Col: 5}, IsLandscape: true},
+	{Coord: board.Coord{Row: 4, Col: 2}},
+	{Coord: board.Coord{Row: 4, Col: 9}, IsLandscape: true},
+	{Coord: board.Coord{Row: 2, Col: 0}},
+	{Coord: board.Coord{Row: 2, Col: 1}},
+}
+
+var sampleMoves = []string{"f5", "a5", "a3", "h3", "h5", "d5", "b5", "g5", "g8", "g3", "g7", "g6", "f6", "f8"}
+
+func newSampleBoard(t *testing.T, numMoves int, opts ...board.Option) *board.KulamiBoard {
+	t.Helper()
+	b, err := board.New(sampleTiles, opts...)
+	if err != nil {
+		t.Fatalf("Error initializing board: %v", err)
+	}
+	for _, s := range sampleMoves[:numMoves] {
+		m, err := board.ParseCoord(s)
+		if err != nil {
+			t.Fatalf("ParseCoord(%q) failed: %v", s, err)
+		}
+		if err := b.Move(m, b.IsRedsTurn()); err != nil {
+			t.Fatalf("Move(%v) failed: %v", m, err)
+		}
+	}
+	return b
+}
+
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestTextPlain(t *testing.T) {
+	for _, n := range []int{0, 1, len(sampleMoves)} {
+		b := newSampleBoard(t, n, board.WithScoring(board.GroupScoring))
+		if got, want := Text(b), b.String(); got != want {
+			t.Errorf("Text() after %d moves returned:\n%s\nExpected:\n%s\n", n, got, want)
+		}
+	}
+}
+
+func TestTextOverlays(t *testing.T) {
+	b := newSampleBoard(t, len(sampleMoves))
+	plain := Text(b, LegalMoves(), LastMoveLines())
+	if got, want := strings.Count(plain, "+"), len(b.LegalMoves()); got != want {
+		t.Errorf("Text() marks %d legal moves, want %d:\n%s", got, want, plain)
+	}
+	lines := strings.Split(plain, "\n")
+	if want := "*     a   b   c   d   e   F   g   h   i   j   k"; lines[0] != want {
+		t.Errorf("Text() header = %q, want %q", lines[0], want)
+	}
+	// Row 8 is printed on line 2*8 after the header.
+	if !strings.HasPrefix(lines[16], "8  >") {
+		t.Errorf("Text() row 8 = %q, want it marked with >", lines[16])
+	}
+	if strings.Contains(plain, "\x1b") {
+		t.Errorf("Text() without Color() has escape codes:\n%s", plain)
+	}
+
+	colored := Text(b, Color(), LegalMoves(), LastMoveLines())
+	if got := ansiCodes.ReplaceAllString(colored, ""); got != plain {
+		t.Errorf("Text() with Color() returned, without escape codes:\n%s\nExpected:\n%s\n", got, plain)
+	}
+	// The tied 4 at f8 of the last move and the 6 at d5 owned by Red are
+	// blocked, the 4 at g5 owned by Black and the empty 4 at e1 are open.
+	for _, want := range []string{"\x1b[48;5;137m", "\x1b[48;5;174m", "\x1b[48;5;252m", "\x1b[48;5;180m", marbleColors[board.BlackMarble], ansiLegal} {
+		if !strings.Contains(colored, want) {
+			t.Errorf("Text() with Color() does not contain %q", want)
+		}
+	}
+}